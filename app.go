@@ -0,0 +1,91 @@
+package inject
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultShutdownTimeout is a time that application waits for stop hooks and cleanups on shutdown.
+const DefaultShutdownTimeout = 15 * time.Second
+
+// Shutdowner stops application. Components declare it as constructor parameter to stop application on fatal
+// error.
+//
+//   func NewWorker(shutdowner inject.Shutdowner) *Worker {
+//     return &Worker{onFatal: shutdowner.Shutdown}
+//   }
+type Shutdowner interface {
+	// Shutdown stops application. Run() returns given error. Nil error means graceful shutdown.
+	Shutdown(err error)
+}
+
+// App is an application that runs components of container until shutdown.
+//
+//   app := inject.NewApp(
+//     inject.Provide(NewServer),
+//     inject.Invoke(RegisterRoutes),
+//     inject.ShutdownTimeout(10*time.Second),
+//   )
+//   if err := app.Run(); err != nil {
+//     log.Fatal(err)
+//   }
+type App struct {
+	options  []Option
+	shutdown chan error
+}
+
+// NewApp creates a new application with provided container options. The application provides Shutdowner into
+// container.
+func NewApp(options ...Option) *App {
+	return &App{
+		options:  options,
+		shutdown: make(chan error, 1),
+	}
+}
+
+// Run compiles container, invokes functions of inject.Invoke() options and starts lifecycle. Then it blocks until
+// SIGINT or SIGTERM is received or some component calls Shutdowner. After that, it stops started components and
+// runs cleanups within shutdown timeout. Run returns error of the first failed step.
+func (a *App) Run() error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	options := append([]Option{Provide(func() Shutdowner { return a })}, a.options...)
+	c, err := NewContainer(options...)
+	if err != nil {
+		return err
+	}
+	if err = c.Start(context.Background()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+		defer cancel()
+		_ = c.Cleanup(ctx)
+		return err
+	}
+	select {
+	case <-signals:
+	case err = <-a.shutdown:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+	defer cancel()
+	stopErr := c.Stop(ctx)
+	cleanupErr := c.Cleanup(ctx)
+	switch {
+	case err != nil:
+		return err
+	case stopErr != nil:
+		return stopErr
+	default:
+		return cleanupErr
+	}
+}
+
+// Shutdown stops running application. Only the first call matters.
+func (a *App) Shutdown(err error) {
+	select {
+	case a.shutdown <- err:
+	default:
+	}
+}
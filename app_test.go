@@ -0,0 +1,88 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defval/inject/v2"
+	"github.com/defval/inject/v2/di"
+)
+
+func TestApp(t *testing.T) {
+	t.Run("app stops on signal", func(t *testing.T) {
+		var calls []string
+		app := inject.NewApp(
+			inject.Provide(func(lc di.Lifecycle) (*http.ServeMux, func()) {
+				lc.Append(di.Hook{
+					OnStart: func(ctx context.Context) error {
+						calls = append(calls, "start")
+						return syscall.Kill(syscall.Getpid(), syscall.SIGTERM)
+					},
+					OnStop: func(ctx context.Context) error {
+						calls = append(calls, "stop")
+						return nil
+					},
+				})
+				return NewMux(), func() { calls = append(calls, "cleanup") }
+			}),
+			inject.Invoke(func(mux *http.ServeMux) {
+				calls = append(calls, "invoke")
+			}),
+		)
+		require.NoError(t, app.Run())
+		require.Equal(t, []string{"invoke", "start", "stop", "cleanup"}, calls)
+	})
+
+	t.Run("app returns fatal component error", func(t *testing.T) {
+		var stopped bool
+		app := inject.NewApp(
+			inject.Provide(func(lc di.Lifecycle, shutdowner inject.Shutdowner) *http.ServeMux {
+				lc.Append(di.Hook{
+					OnStart: func(ctx context.Context) error {
+						go shutdowner.Shutdown(errors.New("fatal error"))
+						return nil
+					},
+					OnStop: func(ctx context.Context) error {
+						stopped = true
+						return nil
+					},
+				})
+				return NewMux()
+			}),
+			inject.Invoke(func(mux *http.ServeMux) {}),
+		)
+		require.EqualError(t, app.Run(), "fatal error")
+		require.True(t, stopped)
+	})
+
+	t.Run("app returns invoke error", func(t *testing.T) {
+		app := inject.NewApp(
+			inject.Invoke(func(mux *http.ServeMux) {}),
+		)
+		require.EqualError(t, app.Run(), "could not resolve invoke parameters: *http.ServeMux: not exists in container")
+	})
+
+	t.Run("app returns start error", func(t *testing.T) {
+		var cleaned bool
+		app := inject.NewApp(
+			inject.Provide(func(lc di.Lifecycle) (*http.ServeMux, func()) {
+				lc.Append(di.Hook{
+					OnStart: func(ctx context.Context) error {
+						return errors.New("start error")
+					},
+				})
+				return NewMux(), func() { cleaned = true }
+			}),
+			inject.Invoke(func(mux *http.ServeMux) {}),
+			inject.ShutdownTimeout(time.Second),
+		)
+		require.EqualError(t, app.Run(), "start error")
+		require.True(t, cleaned)
+	})
+}
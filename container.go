@@ -1,26 +1,71 @@
 package inject
 
 import (
+	"context"
+	"net/http"
+	"time"
+
 	"github.com/defval/inject/v2/di"
 )
 
-// New creates a new container with provided options.
+// New creates a new container with provided options. It panics if the container could not be compiled.
+// See NewContainer() for non-panic version.
 func New(options ...Option) *Container {
-	var c = &Container{
-		container: di.New(),
+	c, err := NewContainer(options...)
+	if err != nil {
+		panic(err.Error())
 	}
+	return c
+}
+
+// NewContainer creates a new container with provided options. It returns an error if some constructor has an
+// incorrect signature, type provided twice, some dependency not exists in container or dependencies have a cycle.
+// Functions of inject.Invoke() options are invoked after compile. If some of them fails, instances created before
+// are cleaned up and error is returned.
+//
+//   container, err := inject.NewContainer(options...)
+//   if err != nil {
+//     // wiring failed
+//   }
+func NewContainer(options ...Option) (*Container, error) {
+	return newContainer(&Container{
+		container:       di.New(),
+		shutdownTimeout: DefaultShutdownTimeout,
+		errorHandler:    defaultErrorHandler,
+	}, options)
+}
+
+// newContainer applies options to container, compiles it and invokes functions of inject.Invoke() options.
+func newContainer(c *Container, options []Option) (*Container, error) {
 	// apply options.
 	for _, opt := range options {
 		opt.apply(c)
 	}
-	c.compile()
-	return c
+	if err := c.compile(); err != nil {
+		return nil, err
+	}
+	for _, fn := range c.invokes {
+		if err := c.container.Invoke(fn); err != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+			defer cancel()
+			_ = c.container.Cleanup(ctx)
+			return nil, err
+		}
+	}
+	return c, nil
 }
 
 // Container is a dependency injection container.
 type Container struct {
-	providers []provide
-	container *di.Container
+	providers       []provide
+	factories       []provide
+	supplies        []provide
+	replacements    []provide
+	decorators      []provide
+	invokes         []interface{}
+	shutdownTimeout time.Duration
+	errorHandler    func(w http.ResponseWriter, r *http.Request, err error)
+	container       *di.Container
 }
 
 // Extract populates given target pointer with type instance provided in the container.
@@ -41,22 +86,121 @@ func (c *Container) Extract(target interface{}, options ...ExtractOption) (err e
 	return c.container.Extract(target, params)
 }
 
+// ExtractContext populates given target pointer like Extract() does. Context is passed into every constructor
+// that declares context.Context parameter. Extraction stops if context is done.
+//
+//   func NewDatabase(ctx context.Context, dsn DSN) (*sql.DB, error) {
+//     // dial database with timeout
+//   }
+//
+//   ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//   defer cancel()
+//   var db *sql.DB
+//   err = container.ExtractContext(ctx, &db)
+func (c *Container) ExtractContext(ctx context.Context, target interface{}, options ...ExtractOption) error {
+	var params = di.ExtractParams{}
+	// apply extract options
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	return c.container.ExtractContext(ctx, target, params)
+}
+
 // Invoke invokes custom function. Dependencies of function will be resolved via container.
 func (c *Container) Invoke(fn interface{}) error {
 	return c.container.Invoke(fn)
 }
 
-// Cleanup cleanup container.
-func (c *Container) Cleanup() {
-	c.container.Cleanup()
+// InvokeContext invokes custom function like Invoke() does. Context is passed into the function and every
+// constructor that declares context.Context parameter. Resolving stops if context is done.
+func (c *Container) InvokeContext(ctx context.Context, fn interface{}) error {
+	return c.container.InvokeContext(ctx, fn)
+}
+
+// Warmup builds every singleton of the container before use. Independent singletons are built concurrently, but
+// no more than parallelism at the same time. Zero or negative parallelism means no limit.
+//
+//   if err = container.Warmup(ctx, 4); err != nil {
+//     // some constructor failed
+//   }
+func (c *Container) Warmup(ctx context.Context, parallelism int) error {
+	return c.container.Warmup(ctx, parallelism)
+}
+
+// Cleanup runs cleanup functions of created instances in reverse order: consumers before their dependencies. It
+// returns all cleanup errors and stops when context is done. Repeated calls do nothing.
+//
+//   ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//   defer cancel()
+//   if err = container.Cleanup(ctx); err != nil {
+//     // some cleanup failed
+//   }
+func (c *Container) Cleanup(ctx context.Context) error {
+	return c.container.Cleanup(ctx)
+}
+
+// Scope creates a child container with provided options. The child resolves types of the container and can
+// provide its own types. Singletons of the child live only in the scope: Cleanup() of the child cleans up the child
+// instances only.
+//
+//   scope, err := container.Scope(
+//     inject.Provide(NewUnitOfWork),
+//   )
+//   if err != nil {
+//     // wiring failed
+//   }
+//   defer scope.Cleanup(ctx)
+func (c *Container) Scope(options ...Option) (*Container, error) {
+	return newContainer(c.child(), options)
+}
+
+// child creates not compiled child container with settings of the container.
+func (c *Container) child() *Container {
+	return &Container{
+		container:       c.container.Scope(),
+		shutdownTimeout: c.shutdownTimeout,
+		errorHandler:    c.errorHandler,
+	}
+}
+
+// Start runs start hooks of created components in dependency order. If some hook fails, started components are
+// stopped and error is returned.
+func (c *Container) Start(ctx context.Context) error {
+	return c.container.Start(ctx)
 }
 
-func (c *Container) compile() {
+// Stop runs stop hooks of started components in reverse dependency order.
+func (c *Container) Stop(ctx context.Context) error {
+	return c.container.Stop(ctx)
+}
+
+func (c *Container) compile() error {
 	for _, po := range c.providers {
-		c.container.Provide(po.provider, po.params)
+		if err := c.container.TryProvide(po.provider, po.params); err != nil {
+			return err
+		}
+	}
+	for _, fo := range c.factories {
+		if err := c.container.TryFactory(fo.provider, fo.params); err != nil {
+			return err
+		}
+	}
+	for _, so := range c.supplies {
+		if err := c.container.TrySupply(so.provider, so.params); err != nil {
+			return err
+		}
+	}
+	for _, ro := range c.replacements {
+		if err := c.container.TryReplace(ro.provider, ro.params); err != nil {
+			return err
+		}
+	}
+	for _, do := range c.decorators {
+		if err := c.container.TryDecorate(do.provider, do.params); err != nil {
+			return err
+		}
 	}
-	c.container.Compile()
-	return
+	return c.container.TryCompile()
 }
 
 type provide struct {
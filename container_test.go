@@ -1,6 +1,7 @@
 package inject_test
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/defval/inject/v2"
+	"github.com/defval/inject/v2/di"
 )
 
 func TestContainer(t *testing.T) {
@@ -32,6 +34,284 @@ func TestContainer(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestContainerContext(t *testing.T) {
+	type key struct{}
+	c := inject.New(
+		inject.Provide(func(ctx context.Context) Addr {
+			return Addr(ctx.Value(key{}).(string))
+		}),
+	)
+	ctx := context.WithValue(context.Background(), key{}, "0.0.0.0:8080")
+	var addr Addr
+	require.NoError(t, c.ExtractContext(ctx, &addr))
+	require.Equal(t, Addr("0.0.0.0:8080"), addr)
+	require.NoError(t, c.InvokeContext(ctx, func(invokeCtx context.Context, addr Addr) {
+		require.Equal(t, ctx, invokeCtx)
+	}))
+}
+
+func TestContainerWarmup(t *testing.T) {
+	var muxCreated bool
+	c := inject.New(
+		inject.Provide(func() *http.ServeMux {
+			muxCreated = true
+			return NewMux()
+		}),
+	)
+	require.NoError(t, c.Warmup(context.Background(), 0))
+	require.True(t, muxCreated)
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	var calls []string
+	c := inject.New(
+		inject.Provide(func(lc di.Lifecycle) *http.ServeMux {
+			lc.Append(di.Hook{
+				OnStart: func(ctx context.Context) error {
+					calls = append(calls, "start")
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					calls = append(calls, "stop")
+					return nil
+				},
+			})
+			return NewMux()
+		}),
+	)
+	require.NoError(t, c.Warmup(context.Background(), 0))
+	require.NoError(t, c.Start(context.Background()))
+	require.NoError(t, c.Stop(context.Background()))
+	require.Equal(t, []string{"start", "stop"}, calls)
+}
+
+func TestContainerScope(t *testing.T) {
+	var cleaned bool
+	c := inject.New(
+		inject.Provide(ProvideAddr("0.0.0.0", "8080")),
+	)
+	scope, err := c.Scope(
+		inject.Provide(func(addr Addr) (*http.Server, func()) {
+			return &http.Server{Addr: string(addr)}, func() { cleaned = true }
+		}),
+	)
+	require.NoError(t, err)
+	var server *http.Server
+	require.NoError(t, scope.Extract(&server))
+	require.Equal(t, "0.0.0.0:8080", server.Addr)
+	require.Error(t, c.Extract(&server))
+	require.NoError(t, scope.Cleanup(context.Background()))
+	require.True(t, cleaned)
+}
+
+func TestContainerDecorate(t *testing.T) {
+	c := inject.New(
+		inject.Provide(ProvideAddr("0.0.0.0", "8080"), inject.WithName("addr")),
+		inject.Decorate(func(addr Addr) Addr {
+			return "http://" + addr
+		}, inject.WithName("addr")),
+	)
+	var addr Addr
+	require.NoError(t, c.Extract(&addr, inject.Name("addr")))
+	require.Equal(t, Addr("http://0.0.0.0:8080"), addr)
+}
+
+func TestContainerSupply(t *testing.T) {
+	mux := &http.ServeMux{}
+	c := inject.New(
+		inject.Supply(Addr("0.0.0.0:8080")),
+		inject.Supply(mux, inject.WithName("mux"), inject.As(new(http.Handler))),
+	)
+	var addr Addr
+	require.NoError(t, c.Extract(&addr))
+	require.Equal(t, Addr("0.0.0.0:8080"), addr)
+	var handler http.Handler
+	require.NoError(t, c.Extract(&handler, inject.Name("mux")))
+	require.Equal(t, mux, handler)
+}
+
+func TestContainerSupplyParameterBag(t *testing.T) {
+	c := inject.New(
+		inject.Supply(inject.ParameterBag{"addr": "0.0.0.0:8080"}),
+	)
+	var bag inject.ParameterBag
+	require.NoError(t, c.Extract(&bag))
+	require.Equal(t, "0.0.0.0:8080", bag["addr"])
+}
+
+// Handlers selects named handler groups.
+type Handlers struct {
+	di.Parameter
+	Public []http.Handler `di:"public"`
+	Admin  []http.Handler `di:"admin"`
+}
+
+func TestContainerNamedGroups(t *testing.T) {
+	c := inject.New(
+		inject.Provide(NewMux, inject.As(new(http.Handler), inject.Group("public"))),
+		inject.Provide(func() *AdminHandler { return &AdminHandler{} }, inject.As(new(http.Handler), inject.Group("admin"), inject.Binding("admin"))),
+		inject.Provide(func(handlers Handlers) *Router {
+			return &Router{public: handlers.Public, admin: handlers.Admin}
+		}),
+	)
+	public := inject.MustExtract[[]http.Handler](c, inject.Name("public"))
+	require.Len(t, public, 1)
+	router := inject.MustExtract[*Router](c)
+	require.Equal(t, public, router.public)
+	require.Len(t, router.admin, 1)
+	admin := inject.MustExtract[http.Handler](c, inject.Name("admin"))
+	require.Equal(t, router.admin[0], admin)
+}
+
+func TestContainerMapGroup(t *testing.T) {
+	c := inject.New(
+		inject.Provide(NewMux, inject.WithName("public"), inject.As(new(http.Handler))),
+		inject.Provide(func() *AdminHandler { return &AdminHandler{} }, inject.WithName("admin"), inject.As(new(http.Handler))),
+	)
+	handlers := inject.MustExtract[map[string]http.Handler](c)
+	require.Len(t, handlers, 2)
+	require.Equal(t, inject.MustExtract[*http.ServeMux](c, inject.Name("public")), handlers["public"])
+	require.IsType(t, &AdminHandler{}, handlers["admin"])
+}
+
+func TestContainerGroupPriority(t *testing.T) {
+	c := inject.New(
+		inject.Provide(func() *AdminHandler { return &AdminHandler{} }, inject.As(new(http.Handler))),
+		inject.Provide(NewMux, inject.As(new(http.Handler)), inject.Priority(-1)),
+	)
+	handlers := inject.MustExtract[[]http.Handler](c)
+	require.Len(t, handlers, 2)
+	require.IsType(t, &http.ServeMux{}, handlers[0])
+	require.IsType(t, &AdminHandler{}, handlers[1])
+}
+
+func TestContainerPrimary(t *testing.T) {
+	c := inject.New(
+		inject.Provide(NewMux, inject.As(new(http.Handler))),
+		inject.Provide(func() *AdminHandler { return &AdminHandler{} }, inject.As(new(http.Handler)), inject.Primary()),
+	)
+	require.IsType(t, &AdminHandler{}, inject.MustExtract[http.Handler](c))
+	require.Len(t, inject.MustExtract[[]http.Handler](c), 2)
+}
+
+func TestContainerAutoBind(t *testing.T) {
+	c := inject.New(
+		inject.AutoBind(),
+		inject.Provide(NewMux),
+		inject.Provide(func(handler http.Handler) *http.Server { return &http.Server{Handler: handler} }),
+	)
+	server := inject.MustExtract[*http.Server](c)
+	require.Equal(t, inject.MustExtract[*http.ServeMux](c), server.Handler)
+	require.Len(t, inject.MustExtract[[]http.Handler](c), 1)
+}
+
+// AdminHandler is a handler of admin group.
+type AdminHandler struct{}
+
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+
+// Router is a consumer of named handler groups.
+type Router struct {
+	public []http.Handler
+	admin  []http.Handler
+}
+
+// Servers is a result object with two servers.
+type Servers struct {
+	di.Result
+	Public  *http.Server `di:"public"`
+	Private *http.Server `di:"private"`
+}
+
+func TestContainerResults(t *testing.T) {
+	c := inject.New(
+		inject.Provide(func() (Addr, *http.ServeMux) {
+			return "0.0.0.0:8080", NewMux()
+		}, inject.As(new(http.Handler))),
+		inject.Provide(func(addr Addr, handler http.Handler) Servers {
+			return Servers{
+				Public:  &http.Server{Addr: string(addr), Handler: handler},
+				Private: &http.Server{Addr: "127.0.0.1:9090", Handler: handler},
+			}
+		}),
+	)
+	public := inject.MustExtract[*http.Server](c, inject.Name("public"))
+	require.Equal(t, "0.0.0.0:8080", public.Addr)
+	private := inject.MustExtract[*http.Server](c, inject.Name("private"))
+	require.Equal(t, "127.0.0.1:9090", private.Addr)
+	require.Equal(t, inject.MustExtract[*http.ServeMux](c), private.Handler)
+}
+
+// ServerArguments are runtime arguments of server factory.
+type ServerArguments struct {
+	di.Arguments
+	Port string
+}
+
+func TestContainerFactory(t *testing.T) {
+	c := inject.New(
+		inject.Provide(NewMux, inject.As(new(http.Handler))),
+		inject.Factory(func(args ServerArguments, handler http.Handler) *http.Server {
+			return &http.Server{Addr: ":" + args.Port, Handler: handler}
+		}),
+	)
+	newServer := inject.MustExtract[func(port string) (*http.Server, error)](c)
+	server, err := newServer("8080")
+	require.NoError(t, err)
+	require.Equal(t, ":8080", server.Addr)
+	require.NotNil(t, server.Handler)
+}
+
+func TestContainerReplace(t *testing.T) {
+	bundle := inject.Bundle(
+		inject.Provide(ProvideAddr("0.0.0.0", "8080")),
+		inject.Provide(NewMux, inject.As(new(http.Handler))),
+	)
+	fake := &http.ServeMux{}
+	c := inject.New(
+		bundle,
+		inject.Replace(func() *http.ServeMux { return fake }),
+	)
+	var handler http.Handler
+	require.NoError(t, c.Extract(&handler))
+	require.Equal(t, fake, handler)
+	var handlers []http.Handler
+	require.NoError(t, c.Extract(&handlers))
+	require.Equal(t, []http.Handler{fake}, handlers)
+}
+
+func TestContainerReplaceSupplied(t *testing.T) {
+	fake := &http.ServeMux{}
+	c := inject.New(
+		inject.Supply(&http.ServeMux{}, inject.As(new(http.Handler))),
+		inject.Replace(func() *http.ServeMux { return fake }),
+	)
+	require.Equal(t, fake, inject.MustExtract[*http.ServeMux](c))
+	require.Equal(t, fake, inject.MustExtract[http.Handler](c))
+}
+
+func TestNewContainer(t *testing.T) {
+	t.Run("returns error on incorrect constructor", func(t *testing.T) {
+		c, err := inject.NewContainer(inject.Provide("string"))
+		require.Nil(t, c)
+		require.EqualError(t, err, "The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `string`")
+	})
+
+	t.Run("returns error on not existing dependency", func(t *testing.T) {
+		c, err := inject.NewContainer(inject.Provide(NewHTTPServer))
+		require.Nil(t, c)
+		require.EqualError(t, err, `compile failed with 2 errors:
+	*http.Server: dependency inject_test.Addr not exists in container
+	*http.Server: dependency http.Handler not exists in container`)
+	})
+
+	t.Run("new panics on error", func(t *testing.T) {
+		require.Panics(t, func() {
+			inject.New(inject.Provide(NewHTTPServer))
+		})
+	})
+}
+
 // Addr
 type Addr string
 
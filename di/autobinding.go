@@ -0,0 +1,165 @@
+package di
+
+import (
+	"reflect"
+)
+
+// EnableAutoBinding enables automatic interface binding. Dependency on interface without provider is resolved by
+// the only provided type with the same name that implements the interface. Group of interface collects all provided
+// types that implement it, explicit members of the group too. Several implementations of the interface cause
+// compile error for its consumers. It must be called before Compile().
+//
+//   c.EnableAutoBinding()
+//   c.Provide(NewServeMux)
+//   c.Provide(func(handler http.Handler) *http.Server { ... }) // *http.ServeMux is used as http.Handler
+func (c *Container) EnableAutoBinding() {
+	c.autoBinding = true
+}
+
+// autoBind adds implementations into groups of interfaces and adds interfaces and groups of interfaces that
+// container parameters depend on but have no providers.
+func (c *Container) autoBind() {
+	// groups of the container and its parents collect all implementations
+	for scope := c; scope != nil; scope = scope.parent {
+		for _, node := range scope.graph.Nodes() {
+			group, ok := node.Value.(*providerGroup)
+			if !ok || group.Key().name != "" {
+				continue
+			}
+			if scope == c {
+				c.bindGroup(group)
+				continue
+			}
+			c.addGroup(parameter{res: group.Key().res})
+		}
+	}
+	for _, node := range c.graph.Nodes() {
+		for _, param := range node.Value.(internalProvider).ParameterList() {
+			if dependency, ok := param.lazyOf(); ok {
+				if _, exists := param.ResolveProvider(c); !exists {
+					param = dependency
+				}
+			}
+			if c.addGroup(param) {
+				continue
+			}
+			if _, exists := param.ResolveProvider(c); exists {
+				continue
+			}
+			if provider, ok := param.autoBindingProvider(c); ok {
+				c.graph.Add(provider.Key(), provider)
+			}
+		}
+	}
+}
+
+// addGroup adds group of parameter if it has implementations that groups of parents do not have. It returns false
+// if parameter is not a group of interface.
+func (c *Container) addGroup(param parameter) bool {
+	group, ok := param.autoBindingGroup(c)
+	if !ok {
+		return false
+	}
+	if c.graph.Exists(group.Key()) {
+		return true
+	}
+	var members int
+	if parent, ok := c.parentGroup(group.Key()); ok {
+		members = len(parent.members)
+	}
+	if len(group.members) > members {
+		c.graph.Add(group.Key(), group)
+	}
+	return true
+}
+
+// bindGroup adds all implementations of interface into group.
+func (c *Container) bindGroup(group *providerGroup) {
+	for _, provider := range c.implementations(group.Key().res.Elem()) {
+		group.Add(provider.Key(), 0)
+	}
+}
+
+// autoBindingProvider creates interface or group of interface provider from provided types that implement the
+// interface. It returns false if the parameter is not an interface or unnamed group of interface or there are no
+// implementations.
+func (p parameter) autoBindingProvider(c *Container) (internalProvider, bool) {
+	if p.isContext() {
+		return nil, false
+	}
+	if p.res.Kind() == reflect.Interface {
+		var implementations []internalProvider
+		for _, provider := range c.implementations(p.res) {
+			if provider.Key().name == p.name {
+				implementations = append(implementations, provider)
+			}
+		}
+		k := key{name: p.name, res: p.res, typ: ptInterface}
+		switch len(implementations) {
+		case 0:
+			return nil, false
+		case 1:
+			return &providerInterface{res: k, provider: implementations[0]}, true
+		default:
+			return newProviderStub(k, "have several implementations"), true
+		}
+	}
+	group, ok := p.autoBindingGroup(c)
+	if !ok || len(group.members) == 0 {
+		return nil, false
+	}
+	return group, true
+}
+
+// autoBindingGroup creates group of interface with members of parent group and all implementations of the
+// interface. It returns false if the parameter is not an unnamed group of interface.
+func (p parameter) autoBindingGroup(c *Container) (*providerGroup, bool) {
+	var group *providerGroup
+	switch {
+	case p.name != "":
+		return nil, false
+	case p.res.Kind() == reflect.Slice && p.res.Elem().Kind() == reflect.Interface:
+		group = newProviderGroup("", key{res: p.res.Elem()})
+	case p.res.Kind() == reflect.Map && p.res.Key().Kind() == reflect.String && p.res.Elem().Kind() == reflect.Interface:
+		group = newProviderMap("", key{res: p.res.Elem()})
+	default:
+		return nil, false
+	}
+	if group.Key().res != p.res {
+		return nil, false
+	}
+	if parent, ok := c.parentGroup(group.Key()); ok {
+		group.members = append(group.members, parent.members...)
+	}
+	c.bindGroup(group)
+	return group, true
+}
+
+// implementations finds provided types of the container and its parents that implement the interface. Types of
+// parents go first.
+func (c *Container) implementations(iface reflect.Type) []internalProvider {
+	var scopes []*Container
+	for scope := c; scope != nil; scope = scope.parent {
+		scopes = append([]*Container{scope}, scopes...)
+	}
+	var providers []internalProvider
+	seen := map[key]bool{}
+	for _, scope := range scopes {
+		for _, node := range scope.graph.Nodes() {
+			provider := node.Value.(internalProvider)
+			k := provider.Key()
+			if k.typ != ptConstructor && k.typ != ptSupplied || k.res == graphType || !k.res.Implements(iface) {
+				continue
+			}
+			if seen[key{name: k.name, res: k.res}] {
+				continue
+			}
+			seen[key{name: k.name, res: k.res}] = true
+			providers = append(providers, provider)
+		}
+	}
+	return providers
+}
+
+// graphType is a type of container graph that is not bound automatically.
+var graphType = reflect.TypeOf(&Graph{})
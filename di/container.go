@@ -1,8 +1,10 @@
 package di
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"sync"
 
 	"github.com/defval/inject/v2/di/internal/graphkv"
 	"github.com/defval/inject/v2/di/internal/reflection"
@@ -22,33 +24,315 @@ type Builder interface {
 // New create new container.
 func New() *Container {
 	return &Container{
-		graph: graphkv.New(),
+		graph:     graphkv.New(),
+		lifecycle: newLifecycle(),
 	}
 }
 
-// Container is a dependency injection container.
+// Container is a dependency injection container. After compilation it is safe for concurrent Extract() and
+// Invoke() calls, each singleton is constructed exactly once.
 type Container struct {
-	compiled bool
-	graph    *graphkv.Graph
-	cleanups []func()
+	parent      *Container
+	compiled    bool
+	internals   bool // graph, interactor and lifecycle are provided
+	autoBinding bool
+	graph       *graphkv.Graph
+	mu          sync.Mutex // guards cleanups
+	cleanups    []cleanup
+	lifecycle   *lifecycle
 }
 
-// Provide adds constructor into container with parameters.
+// cleanup is a destructor of created instance.
+type cleanup struct {
+	k  key
+	fn func() error
+}
+
+// Scope creates a child container. The child resolves types of the container and can provide its own types, its
+// types override types of the container. Types provided in the container are built by the container and shared
+// between scopes, types provided in the child are built by the child: Cleanup() of the child runs destructors of
+// the child instances only. Prototypes of the container created for the child are owned by the child. Interface
+// groups of the child contain implementations of the container too. The child must be compiled after the container.
+//
+//   scope := c.Scope()
+//   scope.Provide(NewRequestLogger)
+//   scope.Compile()
+//   defer scope.Cleanup(ctx)
+func (c *Container) Scope() *Container {
+	return &Container{
+		parent:      c,
+		autoBinding: c.autoBinding,
+		graph:       graphkv.New(),
+		lifecycle:   newLifecycle(),
+	}
+}
+
+// Provide adds constructor into container with parameters. It panics if the constructor could not be added.
+// See TryProvide() for non-panic version.
 func (c *Container) Provide(constructor interface{}, options ...ProvideOption) {
+	if err := c.TryProvide(constructor, options...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryProvide adds constructor into container with parameters. It returns an error if the constructor has an
+// incorrect signature, the type already exists in container or the type does not implement a requested interface.
+func (c *Container) TryProvide(constructor interface{}, options ...ProvideOption) error {
 	params := ProvideParams{}
 	for _, opt := range options {
 		opt.apply(&params)
 	}
-	provider := internalProvider(newProviderConstructor(params.Name, constructor))
+	if isResultsConstructor(constructor) {
+		return c.provideResults(constructor, params)
+	}
+	ctor, err := newProviderConstructor(params.Name, constructor)
+	if err != nil {
+		return err
+	}
+	provider := internalProvider(ctor)
 	key := provider.Key()
-	if c.graph.Exists(key) {
-		panicf("The `%s` type already exists in container", provider.Key())
+	if c.exists(key) {
+		return ErrProviderAlreadyExists{k: key}
+	}
+	if !params.IsPrototype {
+		provider = asSingleton(provider)
+	}
+	// create interfaces before graph modification
+	var ifaces []*providerInterface
+	for _, as := range params.Interfaces {
+		iface, err := newProviderInterface(provider, as)
+		if err != nil {
+			return err
+		}
+		ifaces = append(ifaces, iface)
+	}
+	if err := c.checkPrimary(params, ifaces); err != nil {
+		return err
+	}
+	c.add(provider, params, ifaces)
+	return nil
+}
+
+// Factory adds factory function of constructor into container. Constructor marks runtime arguments with a structure
+// that embeds Arguments. Container provides function that takes exported fields of the structure and returns
+// constructor result and error, other constructor parameters are resolved from container once. If constructor
+// returns cleanup, the factory returns it before error and the caller runs it. Only the name option is used. It
+// panics if the factory could not be added. See TryFactory() for non-panic version.
+//
+//   type WorkerArguments struct {
+//     di.Arguments
+//     JobID string
+//   }
+//
+//   c.Factory(func(args WorkerArguments, db *sql.DB) *Worker {
+//     return &Worker{jobID: args.JobID, db: db}
+//   })
+//
+//   c.Invoke(func(newWorker func(jobID string) (*Worker, error)) {})
+func (c *Container) Factory(constructor interface{}, options ...ProvideOption) {
+	if err := c.TryFactory(constructor, options...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryFactory adds factory function of constructor into container like Factory() does. It returns an error if the
+// constructor has an incorrect signature or has no arguments structure, or the factory already exists in container.
+func (c *Container) TryFactory(constructor interface{}, options ...ProvideOption) error {
+	params := ProvideParams{}
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	factory, err := newProviderFactory(params.Name, constructor)
+	if err != nil {
+		return err
+	}
+	if c.exists(factory.Key()) {
+		return ErrProviderAlreadyExists{k: factory.Key()}
+	}
+	c.add(asSingleton(factory), ProvideParams{Name: params.Name}, nil)
+	return nil
+}
+
+// Supply adds ready-made value into container. The value is registered by its dynamic type, interfaces and name are
+// set by options. Cleanup is never run for the supplied value. It panics if the value could not be added. See
+// TrySupply() for non-panic version.
+//
+//   c.Supply(&Config{Addr: ":8080"})
+func (c *Container) Supply(value interface{}, options ...ProvideOption) {
+	if err := c.TrySupply(value, options...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TrySupply adds ready-made value into container like Supply() does. It returns an error if the value is nil, the
+// type already exists in container or the value does not implement a requested interface.
+func (c *Container) TrySupply(value interface{}, options ...ProvideOption) error {
+	params := ProvideParams{}
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	provider, err := newProviderSupplied(params.Name, value)
+	if err != nil {
+		return err
+	}
+	if c.exists(provider.Key()) {
+		return ErrProviderAlreadyExists{k: provider.Key()}
+	}
+	var ifaces []*providerInterface
+	for _, as := range params.Interfaces {
+		iface, err := newProviderInterface(provider, as)
+		if err != nil {
+			return err
+		}
+		ifaces = append(ifaces, iface)
+	}
+	if err := c.checkPrimary(params, ifaces); err != nil {
+		return err
+	}
+	c.add(provider, params, ifaces)
+	return nil
+}
+
+// exists checks that constructor or supplied value of the key type exists in container.
+func (c *Container) exists(k key) bool {
+	for _, typ := range []providerType{ptConstructor, ptSupplied} {
+		if c.graph.Exists(key{name: k.name, res: k.res, typ: typ}) {
+			return true
+		}
+	}
+	return false
+}
+
+// provideResults adds constructor with several results into container. Constructor is called once for all its
+// results, every result is a separate type. Interfaces are applied to the results that implement them or to the
+// fields of result object with `as` tag option.
+func (c *Container) provideResults(constructor interface{}, params ProvideParams) error {
+	results, err := newProviderResults(params.Name, constructor)
+	if err != nil {
+		return err
+	}
+	resultsProvider := internalProvider(results)
+	if !params.IsPrototype {
+		resultsProvider = asSingleton(resultsProvider)
+	}
+	// create results and interfaces before graph modification
+	var providers []internalProvider
+	keys := map[key]bool{}
+	for i := range results.fields {
+		provider := internalProvider(newProviderResult(results, i))
+		if c.exists(provider.Key()) || keys[provider.Key()] {
+			return ErrProviderAlreadyExists{k: provider.Key()}
+		}
+		keys[provider.Key()] = true
+		if !params.IsPrototype {
+			provider = asSingleton(provider)
+		}
+		providers = append(providers, provider)
+	}
+	// fields with `as` tag option are the only ones bound to interfaces
+	explicit := false
+	for _, field := range results.fields {
+		explicit = explicit || field.as
+	}
+	ifaces := make([][]*providerInterface, len(providers))
+	for _, as := range params.Interfaces {
+		implemented := false
+		for i, provider := range providers {
+			if explicit && !results.fields[i].as {
+				continue
+			}
+			iface, err := newProviderInterface(provider, as)
+			if _, ok := err.(ErrNotImplementInterface); ok && !explicit {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			implemented = true
+			ifaces[i] = append(ifaces[i], iface)
+		}
+		if !implemented {
+			_, err := newProviderInterface(providers[0], as)
+			return err
+		}
+	}
+	for i := range providers {
+		if err := c.checkPrimary(params, ifaces[i]); err != nil {
+			return err
+		}
+	}
+	c.add(resultsProvider, params, nil)
+	for i, provider := range providers {
+		c.add(provider, ProvideParams{Priority: params.Priority, Primary: params.Primary}, ifaces[i])
+	}
+	return nil
+}
+
+// Replace replaces provided type with a new constructor. Interfaces and groups of the replaced type are kept.
+// It panics if the constructor could not be replaced. See TryReplace() for non-panic version.
+//
+//   c.Provide(NewUserRepository, di.As(new(UserStorage)))
+//   c.Replace(NewFakeUserRepository)
+func (c *Container) Replace(constructor interface{}, options ...ProvideOption) {
+	if err := c.TryReplace(constructor, options...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryReplace replaces provided type with a new constructor like Replace() does. It returns an error if the
+// constructor has an incorrect signature, the type does not exist in container or the container already compiled.
+// Supplied value could be replaced too.
+func (c *Container) TryReplace(constructor interface{}, options ...ProvideOption) error {
+	params := ProvideParams{}
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	if c.compiled {
+		return ErrAlreadyCompiled{}
+	}
+	ctor, err := newProviderConstructor(params.Name, constructor)
+	if err != nil {
+		return err
+	}
+	provider := internalProvider(ctor)
+	key := provider.Key()
+	if !c.exists(key) {
+		return ErrReplacedNotFound{k: key}
 	}
 	if !params.IsPrototype {
 		provider = asSingleton(provider)
 	}
-	// add provider to graph
-	c.graph.Add(key, provider)
+	var ifaces []*providerInterface
+	for _, as := range params.Interfaces {
+		iface, err := newProviderInterface(provider, as)
+		if err != nil {
+			return err
+		}
+		ifaces = append(ifaces, iface)
+	}
+	if err := c.checkPrimary(params, ifaces); err != nil {
+		return err
+	}
+	// interfaces of replaced provider refer the new one
+	for _, node := range c.graph.Nodes() {
+		iface, ok := node.Value.(*providerInterface)
+		if ok && iface.provider.Key().name == key.name && iface.provider.Key().res == key.res {
+			iface.provider = provider
+		}
+	}
+	// replaced supplied value is removed, constructor takes its place
+	supplied := key
+	supplied.typ = ptSupplied
+	if c.graph.Exists(supplied) {
+		c.graph.Remove(supplied)
+	}
+	c.add(provider, params, ifaces)
+	return nil
+}
+
+// add adds provider with its embed parameters, parameter bag and interfaces into graph.
+func (c *Container) add(provider internalProvider, params ProvideParams, ifaces []*providerInterface) {
+	c.graph.Add(provider.Key(), provider)
 	// parse embed parameters
 	for _, param := range provider.ParameterList() {
 		if param.embed {
@@ -62,35 +346,156 @@ func (c *Container) Provide(constructor interface{}, options ...ProvideOption) {
 		c.graph.Add(parameterBugProvider.Key(), parameterBugProvider)
 	}
 	// process interfaces
-	for _, iface := range params.Interfaces {
-		c.processProviderInterface(provider, iface)
+	for _, iface := range ifaces {
+		iface.primary = params.Primary
+		c.processProviderInterface(provider, iface, params.Priority)
+	}
+}
+
+// Decorate wraps the result of provided type into decorator. Decorator is a function that takes the original
+// instance as the first parameter and its own dependencies as others and returns the decorated instance. Every
+// consumer of the type, including interfaces and groups of the type, receives the decorated instance. Decorators
+// are applied in order of Decorate() calls. Only the name option is used. It panics if the decorator could not be
+// added. See TryDecorate() for non-panic version.
+//
+//   c.Decorate(func(storage Storage, logger *log.Logger) Storage {
+//     return &loggingStorage{storage, logger}
+//   })
+func (c *Container) Decorate(decorator interface{}, options ...ProvideOption) {
+	if err := c.TryDecorate(decorator, options...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryDecorate wraps the result of provided type into decorator like Decorate() does. It returns an error if the
+// decorator has an incorrect signature, the type does not exist in container or it is ambiguous.
+func (c *Container) TryDecorate(decorator interface{}, options ...ProvideOption) error {
+	params := ProvideParams{}
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	if c.compiled {
+		return ErrAlreadyCompiled{}
+	}
+	fn, ctorType, err := inspectDecorator(decorator)
+	if err != nil {
+		return err
+	}
+	param := parameter{name: params.Name, res: fn.In(0)}
+	for _, pt := range providerLookupSequence {
+		k := key{name: param.name, res: param.res, typ: pt}
+		if !c.graph.Exists(k) {
+			continue
+		}
+		if _, ok := c.graph.Get(k).Value.(*providerStub); ok {
+			return ErrDecoratedAmbiguous{k: k}
+		}
+		c.decorate(c.graph.Get(k).Value.(internalProvider), fn, ctorType)
+		return nil
+	}
+	return ErrDecoratedNotFound{k: key{name: param.name, res: param.res}}
+}
+
+// decorate replaces provider of the graph with decorated one. Singletons are decorated once, interfaces of
+// singletons are decorated as singletons. Groups receive decorated interface.
+func (c *Container) decorate(provider internalProvider, fn *reflection.Func, ctorType ctorType) {
+	var decorated *providerDecorator
+	switch p := provider.(type) {
+	case *singletonWrapper:
+		decorated = newProviderDecorator(p.internalProvider, fn, ctorType)
+		p.internalProvider = decorated
+	case *providerInterface:
+		decorated = newProviderDecorator(p, fn, ctorType)
+		switch p.provider.(type) {
+		case *singletonWrapper, *providerSupplied:
+			c.graph.Replace(p.Key(), asSingleton(decorated))
+		default:
+			c.graph.Replace(p.Key(), decorated)
+		}
+		// group members of the provider resolve through decorated interface
+		for _, group := range []*providerGroup{newProviderGroup(p.group, p.Key()), newProviderMap(p.group, p.Key())} {
+			if c.graph.Exists(group.Key()) {
+				c.graph.Get(group.Key()).Value.(*providerGroup).redirect(p.provider.Key(), p.Key())
+			}
+		}
+	case *providerSupplied:
+		decorated = newProviderDecorator(p, fn, ctorType)
+		c.graph.Replace(p.Key(), asSingleton(decorated))
+	default:
+		decorated = newProviderDecorator(provider, fn, ctorType)
+		c.graph.Replace(provider.Key(), decorated)
+	}
+	// parse embed parameters
+	for _, param := range decorated.decorator.ParameterList() {
+		if param.embed {
+			embed := newProviderEmbed(param)
+			c.graph.Add(embed.Key(), embed)
+		}
 	}
 }
 
 // Compile compiles the container. It iterates over all nodes
-// in graph and register their parameters.
+// in graph and register their parameters. It panics if the container could not be compiled.
+// See TryCompile() for non-panic version.
 func (c *Container) Compile() {
+	if err := c.TryCompile(); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryCompile compiles the container. It walks every node of the graph and returns ErrCompileFailed with
+// all missing dependencies, ambiguous interfaces, incorrect `di` tags and cycles.
+func (c *Container) TryCompile() error {
+	if c.compiled {
+		return ErrAlreadyCompiled{}
+	}
+	if c.parent != nil && !c.parent.compiled {
+		return ErrParentNotCompiled{}
+	}
 	graphProvider := func() *Graph { return &Graph{graph: c.graph.DOTGraph()} }
 	interactorProvider := func() Interactor { return c }
-	c.Provide(graphProvider)
-	c.Provide(interactorProvider)
+	lifecycleProvider := func() Lifecycle { return c.lifecycle }
+	for _, provider := range []interface{}{graphProvider, interactorProvider, lifecycleProvider} {
+		// internal types are already provided by the previous failed compilation
+		if c.internals {
+			break
+		}
+		if err := c.TryProvide(provider); err != nil {
+			return err
+		}
+	}
+	c.internals = true
+	if c.autoBinding {
+		c.autoBind()
+	}
+	var errs []error
 	for _, node := range c.graph.Nodes() {
-		c.registerProviderParameters(node.Value.(internalProvider))
+		errs = append(errs, c.registerProviderParameters(node.Value.(internalProvider))...)
 	}
-	if err := c.graph.CheckCycles(); err != nil {
-		panic(err.Error())
+	for _, cycle := range c.graph.Cycles() {
+		errs = append(errs, newErrDependencyCycle(cycle))
+	}
+	if len(errs) != 0 {
+		return ErrCompileFailed{errs: errs}
 	}
 	c.compiled = true
+	return nil
 }
 
 // Extract builds instance of target type and fills target pointer.
 func (c *Container) Extract(target interface{}, options ...ExtractOption) error {
+	return c.ExtractContext(context.Background(), target, options...)
+}
+
+// ExtractContext builds instance of target type and fills target pointer. Context is passed into every
+// constructor that declares context.Context parameter. Resolving stops if context is done.
+func (c *Container) ExtractContext(ctx context.Context, target interface{}, options ...ExtractOption) error {
 	params := ExtractParams{}
 	for _, opt := range options {
 		opt.apply(&params)
 	}
 	if !c.compiled {
-		return fmt.Errorf("container not compiled")
+		return ErrNotCompiled{}
 	}
 	if target == nil {
 		return fmt.Errorf("extract target must be a pointer, got `nil`")
@@ -104,7 +509,7 @@ func (c *Container) Extract(target interface{}, options ...ExtractOption) error
 		res:   typ.Elem(),
 		embed: isEmbedParameter(typ),
 	}
-	value, err := param.ResolveValue(c)
+	value, err := param.ResolveValue(ctx, c)
 	if err != nil {
 		return err
 	}
@@ -115,66 +520,227 @@ func (c *Container) Extract(target interface{}, options ...ExtractOption) error
 
 // Invoke calls provided function.
 func (c *Container) Invoke(fn interface{}, options ...InvokeOption) error {
+	return c.InvokeContext(context.Background(), fn, options...)
+}
+
+// InvokeContext calls provided function. Context is passed into the function and every constructor that declares
+// context.Context parameter. Resolving stops if context is done.
+func (c *Container) InvokeContext(ctx context.Context, fn interface{}, options ...InvokeOption) error {
 	params := InvokeParams{}
 	for _, opt := range options {
 		opt.apply(&params)
 	}
 	if !c.compiled {
-		return fmt.Errorf("container not compiled")
+		return ErrNotCompiled{}
 	}
 	invoker, err := newInvoker(fn)
 	if err != nil {
 		return err
 	}
-	return invoker.Invoke(c)
+	return invoker.Invoke(ctx, c)
+}
+
+// Cleanup runs destructors of consumers before destructors of their dependencies, lazy dependencies created after
+// consumer included. Unrelated instances are cleaned up in reverse order of creation. Cleanup returns
+// ErrCleanupFailed with all destructor errors. If context is done, remaining destructors are skipped and context
+// error is returned as part of ErrCleanupFailed. Repeated calls do nothing.
+func (c *Container) Cleanup(ctx context.Context) error {
+	c.mu.Lock()
+	cleanups := c.cleanupOrder(c.cleanups)
+	c.cleanups = nil
+	c.mu.Unlock()
+	var errs []error
+	for i, cleanup := range cleanups {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, fmt.Errorf("%d cleanups skipped: %w", len(cleanups)-i, err))
+			break
+		}
+		if err := runCleanup(ctx, cleanup.fn); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cleanup.k, err))
+		}
+	}
+	if len(errs) != 0 {
+		return ErrCleanupFailed{errs: errs}
+	}
+	return nil
+}
+
+// Start runs start hooks of created components in dependency order. Components are created by Extract(), Invoke()
+// or Warmup() calls before start. Singletons that implement Starter or Stopper are registered automatically, other
+// components can register hooks using Lifecycle. If some hook fails, already started components are stopped in
+// reverse order and ErrStartFailed is returned.
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.start(ctx)
 }
 
-// Cleanup runs destructors in order that was been created.
-func (c *Container) Cleanup() {
-	for _, cleanup := range c.cleanups {
-		cleanup()
+// Stop runs stop hooks of started components in reverse dependency order. It returns ErrStopFailed with all stop
+// errors.
+func (c *Container) Stop(ctx context.Context) error {
+	return c.lifecycle.stop(ctx)
+}
+
+// runCleanup runs destructor and waits for its completion or context done.
+func runCleanup(ctx context.Context, cleanup func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- cleanup()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 }
 
-// processProviderInterface represents instances as interfaces and groups.
-func (c *Container) processProviderInterface(provider internalProvider, as interface{}) {
-	// create interface from provider
-	iface := newProviderInterface(provider, as)
+// cleanupOrder sorts destructors so that consumers go before their dependencies. Lazy dependency may be created
+// after its consumer, so the order of creation is not enough. Unrelated destructors and destructors of instances
+// that depend on each other keep reverse order of creation.
+func (c *Container) cleanupOrder(cleanups []cleanup) []cleanup {
+	consumers := map[key]map[interface{}]bool{}
+	for _, cleanup := range cleanups {
+		if _, ok := consumers[cleanup.k]; !ok {
+			consumers[cleanup.k] = c.graph.Consumers(cleanup.k)
+		}
+	}
+	// waits reports whether destructor of dependency waits for destructor of consumer
+	waits := func(dependency, consumer key) bool {
+		return consumers[dependency][consumer] && !consumers[consumer][dependency]
+	}
+	// the relation is a strict partial order, so there is always a destructor without waiting
+	ordered := make([]cleanup, 0, len(cleanups))
+	done := make([]bool, len(cleanups))
+	for len(ordered) != len(cleanups) {
+	candidates:
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			if done[i] {
+				continue
+			}
+			for j := range cleanups {
+				if !done[j] && waits(cleanups[i].k, cleanups[j].k) {
+					continue candidates
+				}
+			}
+			done[i] = true
+			ordered = append(ordered, cleanups[i])
+			break
+		}
+	}
+	return ordered
+}
+
+// addCleanup registers destructor of created instance.
+func (c *Container) addCleanup(k key, fn func() error) {
+	c.mu.Lock()
+	c.cleanups = append(c.cleanups, cleanup{k: k, fn: fn})
+	c.mu.Unlock()
+}
+
+// checkPrimary checks that interfaces of primary provider have no other primary implementation.
+func (c *Container) checkPrimary(params ProvideParams, ifaces []*providerInterface) error {
+	if !params.Primary {
+		return nil
+	}
+	for _, iface := range ifaces {
+		if !c.graph.Exists(iface.Key()) {
+			continue
+		}
+		existing, ok := c.graph.Get(iface.Key()).Value.(*providerInterface)
+		if ok && existing.primary && existing.provider.Key() != iface.provider.Key() {
+			return ErrPrimaryAlreadyExists{k: iface.Key(), primary: existing.provider.Key()}
+		}
+	}
+	return nil
+}
+
+// processProviderInterface represents instances as interfaces and groups. Priority orders the instance in groups.
+func (c *Container) processProviderInterface(provider internalProvider, iface *providerInterface, priority int) {
 	key := iface.Key()
 	if c.graph.Exists(key) {
-		stub := newProviderStub(key, "have several implementations")
-		c.graph.Replace(key, stub)
+		existing, ok := c.graph.Get(key).Value.(*providerInterface)
+		switch {
+		case ok && existing.provider == provider:
+			// replaced provider already represented as the interface
+			return
+		case ok && existing.primary:
+			// primary implementation is kept
+		case iface.primary:
+			c.graph.Replace(key, iface)
+		default:
+			stub := newProviderStub(key, "have several implementations")
+			c.graph.Replace(key, stub)
+		}
 	} else {
 		// add interface node
 		c.graph.Add(key, iface)
 	}
-	// create group
-	group := newProviderGroup(key)
-	groupKey := group.Key()
-	// check exists
-	if c.graph.Exists(groupKey) {
-		// if exists use existing group
-		node := c.graph.Get(groupKey)
-		group = node.Value.(*providerGroup)
-	} else {
-		// else add new group to graph
-		c.graph.Add(groupKey, group)
+	// create slice and map groups
+	for _, group := range []*providerGroup{newProviderGroup(iface.group, key), newProviderMap(iface.group, key)} {
+		groupKey := group.Key()
+		// check exists
+		if c.graph.Exists(groupKey) {
+			// if exists use existing group
+			node := c.graph.Get(groupKey)
+			group = node.Value.(*providerGroup)
+		} else {
+			// else add new group to graph with implementations of parent
+			if parent, ok := c.parentGroup(groupKey); ok {
+				group.members = append(group.members, parent.members...)
+			}
+			c.graph.Add(groupKey, group)
+		}
+		// add provider reference into group
+		group.Add(provider.Key(), priority)
 	}
-	// add provider reference into group
-	providerKey := provider.Key()
-	group.Add(providerKey)
 }
 
-// registerProviderParameters registers provider parameters in a dependency graph.
-func (c *Container) registerProviderParameters(p internalProvider) {
+// registerProviderParameters registers provider parameters in a dependency graph. It returns all errors
+// that was found in provider parameters.
+func (c *Container) registerProviderParameters(p internalProvider) (errs []error) {
+	if embed, ok := p.(*providerEmbed); ok {
+		errs = append(errs, embed.errs...)
+	}
 	for _, param := range p.ParameterList() {
-		provider, exists := param.ResolveProvider(c)
+		provider, owner, exists := param.resolveProvider(c)
 		if exists {
-			c.graph.Edge(provider.Key(), p.Key())
+			if _, ok := provider.(*providerStub); ok {
+				errs = append(errs, ErrDependencyAmbiguous{consumer: p.Key(), param: param})
+			}
+			if group, ok := provider.(*providerGroup); ok && group.ambiguous() {
+				errs = append(errs, ErrDependencyAmbiguous{consumer: p.Key(), param: param})
+			}
+			// parent providers are not part of the graph
+			if owner == c {
+				c.graph.Edge(provider.Key(), p.Key())
+			}
 			continue
 		}
-		if !exists && !param.optional {
-			panicf("%s: dependency %s not exists in container", p.Key(), param)
+		if dependency, ok := param.lazyOf(); ok {
+			if provider, owner, exists := dependency.resolveProvider(c); exists {
+				if _, ok := provider.(*providerStub); ok {
+					errs = append(errs, ErrDependencyAmbiguous{consumer: p.Key(), param: dependency})
+				}
+				// lazy dependency is not part of construction order, so it does not create a cycle
+				if owner == c {
+					c.graph.LazyEdge(provider.Key(), p.Key())
+				}
+				continue
+			}
+		}
+		if !exists && !param.optional && !param.isContext() {
+			errs = append(errs, ErrDependencyNotFound{consumer: p.Key(), param: param, suggestions: c.suggestions(param)})
+		}
+	}
+	return errs
+}
+
+// parentGroup finds group in parent containers.
+func (c *Container) parentGroup(k key) (*providerGroup, bool) {
+	for scope := c.parent; scope != nil; scope = scope.parent {
+		if scope.graph.Exists(k) {
+			group, ok := scope.graph.Get(k).Value.(*providerGroup)
+			return group, ok
 		}
 	}
+	return nil, false
 }
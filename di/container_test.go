@@ -1,12 +1,18 @@
 package di_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"net"
 	"net/http"
 	"reflect"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -19,7 +25,15 @@ func TestContainerCompileErrors(t *testing.T) {
 		c := NewTestContainer(t)
 		c.MustProvide(ditest.NewCycleFooBar)
 		c.MustProvide(ditest.NewBar)
-		c.MustCompileError("the graph cannot be cyclic")
+		c.MustCompileError("the graph cannot be cyclic: *ditest.Foo -> *ditest.Bar -> *ditest.Foo")
+	})
+
+	t.Run("dependency cycle through interface shows full path", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func(qux *ditest.Qux) *ditest.Foo { return &ditest.Foo{} })
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustProvide(ditest.NewQux)
+		c.MustCompileError("the graph cannot be cyclic: *ditest.Foo -> *ditest.Qux -> ditest.Fooer -> *ditest.Bar -> *ditest.Foo")
 	})
 
 	t.Run("not existing dependency cause compile error", func(t *testing.T) {
@@ -41,6 +55,60 @@ func TestContainerCompileErrors(t *testing.T) {
 			c.Compile()
 		})
 	})
+
+	t.Run("not existing dependency error suggests non pointer type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func() ditest.Foo { return ditest.Foo{} })
+		c.MustProvide(ditest.NewBar)
+		c.MustCompileError("*ditest.Bar: dependency *ditest.Foo not exists in container (did you mean ditest.Foo?)")
+	})
+
+	t.Run("not existing dependency error suggests named type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideWithName("foo", ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustCompileError("*ditest.Bar: dependency *ditest.Foo not exists in container (did you mean *ditest.Foo[foo]?)")
+	})
+
+	t.Run("not existing dependency error suggests not bound implementation", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvide(ditest.NewQux)
+		c.MustCompileError("*ditest.Qux: dependency ditest.Fooer not exists in container (did you mean *ditest.Bar?)")
+	})
+
+	t.Run("not existing dependency error suggests group", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func() []*ditest.Foo { return nil })
+		c.MustProvide(ditest.NewBar)
+		c.MustCompileError("*ditest.Bar: dependency *ditest.Foo not exists in container (did you mean []*ditest.Foo?)")
+	})
+
+	t.Run("compile collects every error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type Params struct {
+			di.Parameter
+			Logger *log.Logger `di:"a,b,c"`
+		}
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		c.MustProvide(ditest.NewQux)
+		c.MustProvide(func(params Params) bool { return true })
+
+		err := c.TryCompile()
+		require.EqualError(t, err, `compile failed with 4 errors:
+	*ditest.Bar: dependency *ditest.Foo not exists in container
+	*ditest.Baz: dependency *ditest.Foo not exists in container
+	*ditest.Qux: dependency ditest.Fooer have several implementations
+	di_test.Params: incorrect di tag `+"`a,b,c`"+` of field Logger`)
+
+		var compileErr di.ErrCompileFailed
+		require.True(t, errors.As(err, &compileErr))
+		require.Len(t, compileErr.Errors(), 4)
+		require.True(t, errors.As(err, &di.ErrDependencyAmbiguous{}))
+		require.True(t, errors.As(err, &di.ErrInvalidTag{}))
+	})
 }
 
 func TestContainerProvideErrors(t *testing.T) {
@@ -64,9 +132,12 @@ func TestContainerProvideErrors(t *testing.T) {
 		c.MustProvideError(ditest.ConstructorWithoutResult, "The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `github.com/defval/inject/v2/di/internal/ditest.ConstructorWithoutResult`")
 	})
 
-	t.Run("provide constructor with many results cause panic", func(t *testing.T) {
+	t.Run("provide constructor with many results", func(t *testing.T) {
 		c := NewTestContainer(t)
-		c.MustProvideError(ditest.ConstructorWithManyResults, "The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `github.com/defval/inject/v2/di/internal/ditest.ConstructorWithManyResults`")
+		c.MustProvide(ditest.ConstructorWithManyResults)
+		c.MustCompile()
+		c.MustExtract(new(*ditest.Foo))
+		c.MustExtract(new(*ditest.Bar))
 	})
 
 	t.Run("provide constructor with incorrect result error argument", func(t *testing.T) {
@@ -93,6 +164,73 @@ func TestContainerProvideErrors(t *testing.T) {
 	})
 }
 
+func TestContainerTypedErrors(t *testing.T) {
+	t.Run("try provide returns invalid constructor error", func(t *testing.T) {
+		c := di.New()
+		err := c.TryProvide("string")
+		require.True(t, errors.As(err, &di.ErrInvalidConstructor{}))
+		require.EqualError(t, err, "The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `string`")
+	})
+
+	t.Run("try provide returns already exists error", func(t *testing.T) {
+		c := di.New()
+		require.NoError(t, c.TryProvide(ditest.NewFoo))
+		err := c.TryProvide(ditest.NewFoo)
+		require.True(t, errors.As(err, &di.ErrProviderAlreadyExists{}))
+	})
+
+	t.Run("try provide returns interface errors", func(t *testing.T) {
+		c := di.New()
+		err := c.TryProvide(ditest.NewBar, di.As(new(ditest.Barer)))
+		require.True(t, errors.As(err, &di.ErrNotImplementInterface{}))
+		err = c.TryProvide(ditest.NewBar, di.As(new(ditest.Foo)))
+		require.True(t, errors.As(err, &di.ErrInvalidInterface{}))
+		err = c.TryProvide(ditest.NewBar, di.As(nil))
+		require.EqualError(t, err, "<nil>: not a pointer to interface")
+	})
+
+	t.Run("try compile returns dependency not found error", func(t *testing.T) {
+		c := di.New()
+		require.NoError(t, c.TryProvide(ditest.NewBar))
+		err := c.TryCompile()
+		require.True(t, errors.As(err, &di.ErrDependencyNotFound{}))
+		require.EqualError(t, err, "*ditest.Bar: dependency *ditest.Foo not exists in container")
+	})
+
+	t.Run("try compile returns dependency cycle error", func(t *testing.T) {
+		c := di.New()
+		require.NoError(t, c.TryProvide(ditest.NewCycleFooBar))
+		require.NoError(t, c.TryProvide(ditest.NewBar))
+		err := c.TryCompile()
+		var cycleErr di.ErrDependencyCycle
+		require.True(t, errors.As(err, &cycleErr))
+		require.Equal(t, []string{"*ditest.Foo", "*ditest.Bar", "*ditest.Foo"}, cycleErr.Path())
+	})
+
+	t.Run("try compile could be retried after failure", func(t *testing.T) {
+		c := di.New()
+		require.NoError(t, c.TryProvide(ditest.NewBar))
+		require.True(t, errors.As(c.TryCompile(), &di.ErrDependencyNotFound{}))
+		require.NoError(t, c.TryProvide(ditest.NewFoo))
+		require.NoError(t, c.TryCompile())
+		var bar *ditest.Bar
+		require.NoError(t, c.Extract(&bar))
+	})
+
+	t.Run("try compile returns already compiled error", func(t *testing.T) {
+		c := di.New()
+		require.NoError(t, c.TryCompile())
+		require.True(t, errors.As(c.TryCompile(), &di.ErrAlreadyCompiled{}))
+	})
+
+	t.Run("try compile returns parent not compiled error", func(t *testing.T) {
+		c := di.New()
+		err := c.Scope().TryCompile()
+		require.True(t, errors.As(err, &di.ErrParentNotCompiled{}))
+		require.EqualError(t, err, "parent container not compiled")
+	})
+}
+
 func TestContainerExtractErrors(t *testing.T) {
 	t.Run("container panic on trying extract before compilation", func(t *testing.T) {
 		c := NewTestContainer(t)
@@ -100,6 +238,7 @@ func TestContainerExtractErrors(t *testing.T) {
 		c.MustProvide(ditest.CreateFooConstructor(foo))
 		var extracted *ditest.Foo
 		c.MustExtractError(&extracted, "container not compiled")
+		require.True(t, errors.As(c.Extract(&extracted), &di.ErrNotCompiled{}))
 	})
 
 	t.Run("extract into string cause error", func(t *testing.T) {
@@ -130,7 +269,7 @@ func TestContainerExtractErrors(t *testing.T) {
 		c.MustCompile()
 
 		var extracted *ditest.Foo
-		c.MustExtractError(&extracted, "*ditest.Foo: not exists in container")
+		c.MustExtractError(&extracted, "*ditest.Foo: not exists in container (did you mean *ditest.Foo[foo]?)")
 	})
 
 	t.Run("extract returns error because dependency constructing failed", func(t *testing.T) {
@@ -139,7 +278,23 @@ func TestContainerExtractErrors(t *testing.T) {
 		c.MustProvide(ditest.NewBar)
 		c.MustCompile()
 		var bar *ditest.Bar
-		c.MustExtractError(&bar, "*ditest.Foo: internal error")
+		c.MustExtractError(&bar, "*ditest.Bar -> *ditest.Foo: internal error")
+	})
+
+	t.Run("extract error contains dependency chain and constructor error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		internalErr := errors.New("internal error")
+		c.MustProvide(ditest.CreateFooConstructorWithError(internalErr))
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustProvide(ditest.NewQux)
+		c.MustCompile()
+		var qux *ditest.Qux
+		err := c.Extract(&qux)
+		require.EqualError(t, err, "*ditest.Qux -> ditest.Fooer -> *ditest.Bar -> *ditest.Foo: internal error")
+		require.True(t, errors.Is(err, internalErr))
+		var provideErr di.ErrParameterProvideFailed
+		require.True(t, errors.As(err, &provideErr))
+		require.Equal(t, []string{"*ditest.Qux", "ditest.Fooer", "*ditest.Bar", "*ditest.Foo"}, provideErr.Chain())
 	})
 
 	t.Run("extract interface with multiple implementations cause error", func(t *testing.T) {
@@ -169,6 +324,18 @@ func TestContainerInvokeErrors(t *testing.T) {
 		c.MustInvokeError(func(foo *ditest.Foo) {}, "could not resolve invoke parameters: *ditest.Foo: not exists in container")
 	})
 
+	t.Run("invoke error contains dependency chain", func(t *testing.T) {
+		c := NewTestContainer(t)
+		internalErr := errors.New("internal error")
+		c.MustProvide(ditest.CreateFooConstructorWithError(internalErr))
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		err := c.Invoke(func(bar *ditest.Bar) {})
+		require.EqualError(t, err, "could not resolve invoke parameters: *ditest.Bar -> *ditest.Foo: internal error")
+		require.True(t, errors.Is(err, internalErr))
+		require.True(t, errors.As(err, &di.ErrParameterProvideFailed{}))
+	})
+
 	t.Run("invoke before compile cause error", func(t *testing.T) {
 		c := NewTestContainer(t)
 		c.MustInvokeError(func() {}, "container not compiled")
@@ -452,6 +619,61 @@ func TestContainerInvoke(t *testing.T) {
 	})
 }
 
+func TestContainerResolveContext(t *testing.T) {
+	t.Run("container passes extract context into constructor", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type ctxKey struct{}
+		c.MustProvide(func(ctx context.Context) *ditest.Foo {
+			return &ditest.Foo{Name: ctx.Value(ctxKey{}).(string)}
+		})
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		ctx := context.WithValue(context.Background(), ctxKey{}, "foo")
+		var bar *ditest.Bar
+		require.NoError(t, c.ExtractContext(ctx, &bar))
+		require.Equal(t, "foo", bar.Foo().Name)
+	})
+
+	t.Run("container passes invoke context into function", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustCompile()
+		ctx := context.WithValue(context.Background(), struct{}{}, "value")
+		require.NoError(t, c.InvokeContext(ctx, func(invokeCtx context.Context) {
+			require.Equal(t, ctx, invokeCtx)
+		}))
+	})
+
+	t.Run("container uses provided context if exists", func(t *testing.T) {
+		c := NewTestContainer(t)
+		provided := context.WithValue(context.Background(), struct{}{}, "provided")
+		c.MustProvide(func() context.Context { return provided })
+		c.MustCompile()
+		require.NoError(t, c.InvokeContext(context.Background(), func(ctx context.Context) {
+			require.Equal(t, provided, ctx)
+		}))
+	})
+
+	t.Run("container stops resolving on canceled context", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var barCreated bool
+		ctx, cancel := context.WithCancel(context.Background())
+		c.MustProvide(func() *ditest.Foo {
+			cancel()
+			return &ditest.Foo{}
+		})
+		c.MustProvide(func(foo *ditest.Foo) *ditest.Bar {
+			barCreated = true
+			return &ditest.Bar{}
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		err := c.ExtractContext(ctx, &bar)
+		require.EqualError(t, err, "*ditest.Bar: context canceled")
+		require.True(t, errors.Is(err, context.Canceled))
+		require.False(t, barCreated)
+	})
+}
+
 func TestContainerResolveParameterBag(t *testing.T) {
 	t.Run("container extract correct parameter bag for type", func(t *testing.T) {
 		c := NewTestContainer(t)
@@ -493,6 +715,12 @@ func TestContainerResolveParameterBag(t *testing.T) {
 	})
 }
 
+// namedCleanup is a named cleanup function type.
+type namedCleanup func()
+
+// namedErrorCleanup is a named cleanup function type with error.
+type namedErrorCleanup func() error
+
 func TestContainerCleanup(t *testing.T) {
 	t.Run("container run cleanup function after container close", func(t *testing.T) {
 		c := NewTestContainer(t)
@@ -502,12 +730,29 @@ func TestContainerCleanup(t *testing.T) {
 
 		var extracted *ditest.Foo
 		c.MustExtract(&extracted)
-		c.Cleanup()
+		require.NoError(t, c.Cleanup(context.Background()))
 
 		require.True(t, cleanupCalled)
 	})
 
-	t.Run("cleanup run in correct order", func(t *testing.T) {
+	t.Run("container run named cleanup function", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleanups []string
+		c.MustProvide(func() (*ditest.Foo, namedCleanup) {
+			return &ditest.Foo{}, func() { cleanups = append(cleanups, "foo") }
+		})
+		c.MustProvide(func(foo *ditest.Foo) (*ditest.Bar, namedErrorCleanup, error) {
+			return &ditest.Bar{}, func() error { return errors.New("bar error") }, nil
+		})
+		c.MustCompile()
+
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		require.EqualError(t, c.Cleanup(context.Background()), "*ditest.Bar: bar error")
+		require.Equal(t, []string{"foo"}, cleanups)
+	})
+
+	t.Run("cleanup run in reverse dependency order", func(t *testing.T) {
 		c := NewTestContainer(t)
 		var cleanupCalls []string
 		c.MustProvide(func(bar *ditest.Bar) (*ditest.Foo, func()) {
@@ -520,8 +765,8 @@ func TestContainerCleanup(t *testing.T) {
 
 		var foo *ditest.Foo
 		c.MustExtract(&foo)
-		c.Cleanup()
-		require.Equal(t, []string{"bar", "foo"}, cleanupCalls)
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, []string{"foo", "bar"}, cleanupCalls)
 	})
 
 	t.Run("cleanup for every prototyped instance", func(t *testing.T) {
@@ -538,9 +783,1305 @@ func TestContainerCleanup(t *testing.T) {
 		var foo1, foo2 *ditest.Foo
 		c.MustExtract(&foo1)
 		c.MustExtract(&foo2)
-		c.Cleanup()
+		require.NoError(t, c.Cleanup(context.Background()))
 		require.Equal(t, []string{"foo_0", "foo_1"}, cleanupCalls)
 	})
+
+	t.Run("cleanup returns all errors", func(t *testing.T) {
+		c := NewTestContainer(t)
+		closeErr := errors.New("close failed")
+		c.MustProvide(func(bar *ditest.Bar) (*ditest.Foo, func() error) {
+			return &ditest.Foo{}, func() error { return closeErr }
+		})
+		c.MustProvide(func() (*ditest.Bar, func() error, error) {
+			return &ditest.Bar{}, func() error { return errors.New("internal error") }, nil
+		})
+		c.MustCompile()
+
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		err := c.Cleanup(context.Background())
+		require.EqualError(t, err, `cleanup failed with 2 errors:
+	*ditest.Foo: close failed
+	*ditest.Bar: internal error`)
+		require.True(t, errors.Is(err, closeErr))
+		var cleanupErr di.ErrCleanupFailed
+		require.True(t, errors.As(err, &cleanupErr))
+		require.Len(t, cleanupErr.Errors(), 2)
+	})
+
+	t.Run("cleanup stops on context deadline", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var barCleaned bool
+		c.MustProvide(func(bar *ditest.Bar) (*ditest.Foo, func()) {
+			return &ditest.Foo{}, func() { time.Sleep(time.Second) }
+		})
+		c.MustProvide(func() (*ditest.Bar, func()) {
+			return &ditest.Bar{}, func() { barCleaned = true }
+		})
+		c.MustCompile()
+
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		err := c.Cleanup(ctx)
+		require.EqualError(t, err, `cleanup failed with 2 errors:
+	*ditest.Foo: context deadline exceeded
+	1 cleanups skipped: context deadline exceeded`)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.False(t, barCleaned)
+	})
+
+	t.Run("repeated cleanup does nothing", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleanupCalls int
+		c.MustProvide(ditest.CreateFooConstructorWithCleanup(func() { cleanupCalls++ }))
+		c.MustCompile()
+
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, 1, cleanupCalls)
+	})
+}
+
+func TestContainerScope(t *testing.T) {
+	t.Run("scope resolves shared parent singletons and own singletons", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustCompile()
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		var bars []*ditest.Bar
+		for i := 0; i < 2; i++ {
+			scope := &TestContainer{t, c.Scope()}
+			scope.MustProvide(ditest.NewBar)
+			scope.MustCompile()
+			var bar *ditest.Bar
+			scope.MustExtract(&bar)
+			c.MustEqualPointer(foo, bar.Foo())
+			bars = append(bars, bar)
+		}
+		c.MustNotEqualPointer(bars[0], bars[1])
+		c.MustExtractError(new(*ditest.Bar), "*ditest.Bar: not exists in container")
+	})
+
+	t.Run("scope owns parent prototypes created for it", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleanups int
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvidePrototype(func(foo *ditest.Foo) (*ditest.Bar, func()) {
+			return ditest.NewBar(foo), func() { cleanups++ }
+		})
+		c.MustCompile()
+		for i := 1; i <= 3; i++ {
+			scope := &TestContainer{t, c.Scope()}
+			scope.MustCompile()
+			var bar *ditest.Bar
+			scope.MustExtract(&bar)
+			require.NoError(t, scope.Cleanup(context.Background()))
+			require.Equal(t, i, cleanups)
+		}
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, 3, cleanups)
+	})
+
+	t.Run("scope cleanup runs destructors of scope instances only", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleaned []string
+		c.MustProvide(func() (*ditest.Foo, func()) {
+			return &ditest.Foo{}, func() { cleaned = append(cleaned, "foo") }
+		})
+		c.MustCompile()
+		scope := &TestContainer{t, c.Scope()}
+		scope.MustProvide(func(foo *ditest.Foo) (*ditest.Bar, func()) {
+			return ditest.NewBar(foo), func() { cleaned = append(cleaned, "bar") }
+		})
+		scope.MustCompile()
+		var bar *ditest.Bar
+		scope.MustExtract(&bar)
+		require.NoError(t, scope.Cleanup(context.Background()))
+		require.Equal(t, []string{"bar"}, cleaned)
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, []string{"bar", "foo"}, cleaned)
+	})
+
+	t.Run("scope overrides parent type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustCompile()
+		scopeFoo := &ditest.Foo{Name: "scope"}
+		scope := &TestContainer{t, c.Scope()}
+		scope.MustProvide(ditest.CreateFooConstructor(scopeFoo))
+		scope.MustCompile()
+		scope.MustExtractPtr(scopeFoo, new(*ditest.Foo))
+	})
+
+	t.Run("scope group contains parent implementations", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustCompile()
+		scope := &TestContainer{t, c.Scope()}
+		scope.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		scope.MustCompile()
+		var fooers []ditest.Fooer
+		scope.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+		var fooer ditest.Fooer
+		scope.MustExtract(&fooer)
+		require.IsType(t, &ditest.Baz{}, fooer)
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+	})
+
+	t.Run("scope of not compiled container could not be compiled", func(t *testing.T) {
+		c := NewTestContainer(t)
+		scope := &TestContainer{t, c.Scope()}
+		scope.MustCompileError("parent container not compiled")
+	})
+}
+
+func TestContainerSupply(t *testing.T) {
+	t.Run("supplied value resolved by its type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		foo := &ditest.Foo{Name: "supplied"}
+		c.Supply(foo)
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		c.MustExtractPtr(foo, new(*ditest.Foo))
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(foo, bar.Foo())
+	})
+
+	t.Run("supplied value with name and interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		bar := &ditest.Bar{}
+		c.Supply(bar, di.ProvideParams{Name: "bar", Interfaces: []interface{}{new(ditest.Fooer)}})
+		c.MustCompile()
+		c.MustExtractPtrWithName(bar, "bar", new(*ditest.Bar))
+		var fooer ditest.Fooer
+		c.MustExtractWithName("bar", &fooer)
+		c.MustEqualPointer(bar, fooer)
+	})
+
+	t.Run("supplied value in group", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.Supply(&ditest.Baz{}, di.As(new(ditest.Fooer)))
+		c.MustCompile()
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+	})
+
+	t.Run("supplied type already exists", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.EqualError(t, c.TrySupply(&ditest.Foo{}), "The `*ditest.Foo` type already exists in container")
+		c = NewTestContainer(t)
+		c.Supply(&ditest.Foo{})
+		c.MustProvideError(ditest.NewFoo, "The `*ditest.Foo` type already exists in container")
+	})
+
+	t.Run("nil supplied value", func(t *testing.T) {
+		c := NewTestContainer(t)
+		err := c.TrySupply(nil)
+		require.EqualError(t, err, "The supplied value must be not nil")
+		require.IsType(t, di.ErrInvalidSupply{}, err)
+	})
+
+	t.Run("supplied value visualized as a distinct node", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.Supply(&ditest.Foo{})
+		c.MustCompile()
+		var graph *di.Graph
+		c.MustExtract(&graph)
+		require.Contains(t, graph.String(), `n1[color="#3FA34D",fontcolor="white",fontname="COURIER",label="*ditest.Foo",shape="note",style="filled"];`)
+	})
+}
+
+// namedFooers selects named groups.
+type namedFooers struct {
+	di.Parameter
+	Public []ditest.Fooer `di:"public"`
+	Admin  []ditest.Fooer `di:"admin"`
+}
+
+// fooerGroups is a consumer of named groups.
+type fooerGroups struct {
+	public []ditest.Fooer
+	admin  []ditest.Fooer
+}
+
+func TestContainerNamedInterfaces(t *testing.T) {
+	t.Run("named groups are separate", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, di.Interface{Type: new(ditest.Fooer), Group: "public"})
+		c.MustProvide(ditest.NewBaz, di.Interface{Type: new(ditest.Fooer), Group: "admin"})
+		c.MustProvideWithName("other", ditest.NewBar, di.Interface{Type: new(ditest.Fooer), Group: "admin"})
+		c.MustProvide(func(params namedFooers) *fooerGroups {
+			return &fooerGroups{public: params.Public, admin: params.Admin}
+		})
+		c.MustCompile()
+		var public []ditest.Fooer
+		c.MustExtractWithName("public", &public)
+		require.Len(t, public, 1)
+		var admin []ditest.Fooer
+		c.MustExtractWithName("admin", &admin)
+		require.Len(t, admin, 2)
+		c.MustExtractError(new([]ditest.Fooer), "[]ditest.Fooer: not exists in container (did you mean *ditest.Bar or []ditest.Fooer[public] or *ditest.Baz or []ditest.Fooer[admin] or *ditest.Bar[other]?)")
+		var groups *fooerGroups
+		c.MustExtract(&groups)
+		require.Len(t, groups.public, 1)
+		require.Len(t, groups.admin, 2)
+	})
+
+	t.Run("named binding", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, di.Interface{Type: new(ditest.Fooer), Name: "bar"})
+		c.MustProvide(ditest.NewBaz, di.Interface{Type: new(ditest.Fooer), Name: "baz"})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		var fooer ditest.Fooer
+		c.MustExtractWithName("bar", &fooer)
+		c.MustEqualPointer(bar, fooer)
+		c.MustExtractError(new(ditest.Fooer), "ditest.Fooer: not exists in container (did you mean *ditest.Bar or ditest.Fooer[bar] or []ditest.Fooer or *ditest.Baz or ditest.Fooer[baz]?)")
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+	})
+
+	t.Run("incorrect interface binding", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideError(ditest.NewFoo, "*ditest.Foo: not a pointer to interface", di.Interface{Type: &ditest.Foo{}})
+	})
+}
+
+func TestContainerMapGroups(t *testing.T) {
+	t.Run("map group keyed by provider names", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvideWithName("bar", ditest.NewBar, new(ditest.Fooer))
+		c.MustProvideWithName("baz", ditest.NewBaz, new(ditest.Fooer))
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustCompile()
+		var fooers map[string]ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+		var bar *ditest.Bar
+		c.MustExtractWithName("bar", &bar)
+		c.MustEqualPointer(bar, fooers["bar"])
+		require.IsType(t, &ditest.Baz{}, fooers["baz"])
+	})
+
+	t.Run("named map group", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvideWithName("bar", ditest.NewBar, di.Interface{Type: new(ditest.Fooer), Group: "admin"})
+		c.MustCompile()
+		var fooers map[string]ditest.Fooer
+		c.MustExtractWithName("admin", &fooers)
+		require.Len(t, fooers, 1)
+	})
+
+	t.Run("map group with the same names is ambiguous", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvideWithName("fooer", ditest.NewBar, new(ditest.Fooer))
+		c.MustProvideWithName("fooer", ditest.NewBaz, new(ditest.Fooer))
+		c.MustProvide(func(fooers map[string]ditest.Fooer) *fooerGroups { return &fooerGroups{} })
+		c.MustCompileError("*di_test.fooerGroups: dependency map[string]ditest.Fooer have several implementations")
+	})
+}
+
+func TestContainerGroupPriority(t *testing.T) {
+	t.Run("group members ordered by priority", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Priority(10)))
+		require.NoError(t, c.TryProvide(ditest.NewBaz, di.As(new(ditest.Fooer))))
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.ProvideParams{
+			Name:       "first",
+			Interfaces: []interface{}{new(ditest.Fooer)},
+			Priority:   -1,
+		}))
+		c.MustCompile()
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 3)
+		var first, bar *ditest.Bar
+		c.MustExtractWithName("first", &first)
+		c.MustExtract(&bar)
+		c.MustEqualPointer(first, fooers[0])
+		require.IsType(t, &ditest.Baz{}, fooers[1])
+		c.MustEqualPointer(bar, fooers[2])
+	})
+
+	t.Run("members with the same priority keep order of providing", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Priority(1)))
+		require.NoError(t, c.TryProvide(ditest.NewBaz, di.As(new(ditest.Fooer)), di.Priority(1)))
+		c.MustCompile()
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+		require.IsType(t, &ditest.Bar{}, fooers[0])
+		require.IsType(t, &ditest.Baz{}, fooers[1])
+	})
+}
+
+func TestContainerPrimaryInterface(t *testing.T) {
+	t.Run("primary implementation resolves as interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Primary()))
+		c.MustProvide(ditest.NewQux)
+		c.MustCompile()
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(bar, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+	})
+
+	t.Run("primary implementation is kept", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Primary()))
+		c.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		c.MustCompile()
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		require.IsType(t, &ditest.Bar{}, fooer)
+	})
+
+	t.Run("two primary implementations cause error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Primary()))
+		err := c.TryProvide(ditest.NewBaz, di.As(new(ditest.Fooer)), di.Primary())
+		require.EqualError(t, err, "The `ditest.Fooer` interface already has primary implementation `*ditest.Bar`")
+	})
+}
+
+func TestContainerAutoBinding(t *testing.T) {
+	t.Run("interface bound to the only implementation", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvide(ditest.NewQux)
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		var qux *ditest.Qux
+		c.MustExtract(&qux)
+		c.MustEqualPointer(bar, qux.Fooer())
+	})
+
+	t.Run("group collects all implementations", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvide(ditest.NewBaz)
+		c.MustProvide(ditest.NewFooerGroup)
+		c.MustCompile()
+		var group *ditest.FooerGroup
+		c.MustExtract(&group)
+		require.Len(t, group.Fooers(), 2)
+		require.IsType(t, &ditest.Bar{}, group.Fooers()[0])
+		require.IsType(t, &ditest.Baz{}, group.Fooers()[1])
+	})
+
+	t.Run("several implementations cause compile error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvide(ditest.NewBaz)
+		c.MustProvide(ditest.NewQux)
+		c.MustCompileError("*ditest.Qux: dependency ditest.Fooer have several implementations")
+	})
+
+	t.Run("group merges explicit and automatic implementations", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TryProvide(ditest.NewBar, di.As(new(ditest.Fooer)), di.Priority(1)))
+		c.MustProvide(ditest.NewBaz)
+		c.MustProvide(ditest.NewFooerGroup)
+		c.MustCompile()
+		var group *ditest.FooerGroup
+		c.MustExtract(&group)
+		require.Len(t, group.Fooers(), 2)
+		require.IsType(t, &ditest.Baz{}, group.Fooers()[0])
+		require.IsType(t, &ditest.Bar{}, group.Fooers()[1])
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+	})
+
+	t.Run("scope group merges implementations of scope", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustCompile()
+		scope := &TestContainer{t: t, Container: c.Scope()}
+		scope.MustProvide(ditest.NewBaz)
+		scope.MustCompile()
+		var fooers []ditest.Fooer
+		scope.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+	})
+
+	t.Run("explicit interface is used", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		c.MustCompile()
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		require.IsType(t, &ditest.Baz{}, fooer)
+	})
+
+	t.Run("extract binds interface without consumers", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		var fooers map[string]ditest.Fooer
+		c.MustExtractError(&fooers, "map[string]ditest.Fooer: not exists in container")
+	})
+
+	t.Run("scope binds implementations of parent", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.EnableAutoBinding()
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		scope := &TestContainer{t: t, Container: c.Scope()}
+		scope.MustProvide(ditest.NewQux)
+		scope.MustCompile()
+		var qux *ditest.Qux
+		scope.MustExtract(&qux)
+		require.IsType(t, &ditest.Bar{}, qux.Fooer())
+	})
+}
+
+// fooBarResult is a result object of constructor.
+type fooBarResult struct {
+	di.Result
+	Foo    *ditest.Foo `di:"foo"`
+	Bar    *ditest.Bar `di:""`
+	Ignore *ditest.Baz
+}
+
+func TestContainerResults(t *testing.T) {
+	t.Run("constructor called once for all results", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls int
+		var cleaned bool
+		c.MustProvide(func() (*ditest.Foo, *ditest.Bar, func(), error) {
+			calls++
+			foo := &ditest.Foo{}
+			return foo, ditest.NewBar(foo), func() { cleaned = true }, nil
+		})
+		c.MustProvide(ditest.NewBaz)
+		c.MustCompile()
+		var baz *ditest.Baz
+		c.MustExtract(&baz)
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(foo, bar.Foo())
+		c.MustEqualPointer(bar, baz.Bar())
+		require.Equal(t, 1, calls)
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.True(t, cleaned)
+	})
+
+	t.Run("results provided as implemented interfaces", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func() (*ditest.Foo, *ditest.Bar) {
+			return &ditest.Foo{}, &ditest.Bar{}
+		}, new(ditest.Fooer))
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+	})
+
+	t.Run("results not implement interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideError(func() (*ditest.Foo, *ditest.Bar) {
+			return &ditest.Foo{}, &ditest.Bar{}
+		}, "*ditest.Foo not implement ditest.Barer", new(ditest.Barer))
+	})
+
+	t.Run("constructor error returned for every result", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func() (*ditest.Foo, *ditest.Bar, error) {
+			return nil, nil, errors.New("results error")
+		})
+		c.MustCompile()
+		c.MustExtractError(new(*ditest.Bar), "*ditest.Bar -> func() (*ditest.Foo, *ditest.Bar, error): results error")
+	})
+
+	t.Run("result object fields provided with names", func(t *testing.T) {
+		c := NewTestContainer(t)
+		foo := &ditest.Foo{}
+		c.MustProvide(func() fooBarResult {
+			return fooBarResult{Foo: foo, Bar: ditest.NewBar(foo), Ignore: &ditest.Baz{}}
+		}, new(ditest.Fooer))
+		c.MustCompile()
+		c.MustExtractPtrWithName(foo, "foo", new(*ditest.Foo))
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(foo, bar.Foo())
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		c.MustExtractError(new(*ditest.Baz), "*ditest.Baz: not exists in container")
+	})
+
+	t.Run("result object with incorrect tag", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type result struct {
+			di.Result
+			Foo *ditest.Foo `di:"foo,optional"`
+		}
+		c.MustProvideError(func() result { return result{} }, "func() di_test.result: incorrect di tag `foo,optional` of field Foo")
+	})
+
+	t.Run("result object field bound to interface by tag", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type result struct {
+			di.Result
+			Bar *ditest.Bar `di:"as"`
+			Baz *ditest.Baz `di:""`
+		}
+		bar, baz := &ditest.Bar{}, &ditest.Baz{}
+		c.MustProvide(func() result { return result{Bar: bar, Baz: baz} }, new(ditest.Fooer))
+		c.MustCompile()
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+	})
+
+	t.Run("result object fields implement the same interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type result struct {
+			di.Result
+			Bar *ditest.Bar `di:""`
+			Baz *ditest.Baz `di:""`
+		}
+		c.MustProvide(func() result { return result{} }, new(ditest.Fooer))
+		c.MustCompile()
+		c.MustExtractError(new(ditest.Fooer), "ditest.Fooer: have several implementations")
+	})
+
+	t.Run("result object field with as tag must implement interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		type result struct {
+			di.Result
+			Foo *ditest.Foo `di:"foo,as"`
+			Bar *ditest.Bar `di:""`
+		}
+		c.MustProvideError(func() result { return result{} }, "*ditest.Foo[foo] not implement ditest.Fooer", new(ditest.Fooer))
+	})
+
+	t.Run("duplicated result", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideError(func() (*ditest.Foo, *ditest.Foo) {
+			return nil, nil
+		}, "The `*ditest.Foo` type already exists in container")
+	})
+}
+
+// worker is a factory test component.
+type worker struct {
+	jobID string
+	retry int
+	foo   *ditest.Foo
+}
+
+// workerArguments are runtime arguments of worker.
+type workerArguments struct {
+	di.Arguments
+	JobID string
+	Retry int
+}
+
+func TestContainerFactory(t *testing.T) {
+	t.Run("factory mixes runtime arguments with dependencies", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		var cleaned []string
+		c.Factory(func(foo *ditest.Foo, args workerArguments) (*worker, func(), error) {
+			return &worker{jobID: args.JobID, retry: args.Retry, foo: foo}, func() { cleaned = append(cleaned, args.JobID) }, nil
+		})
+		c.MustCompile()
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		var newWorker func(jobID string, retry int) (*worker, func(), error)
+		c.MustExtract(&newWorker)
+		first, cleanupFirst, err := newWorker("first", 1)
+		require.NoError(t, err)
+		require.Equal(t, &worker{jobID: "first", retry: 1, foo: foo}, first)
+		second, cleanupSecond, err := newWorker("second", 2)
+		require.NoError(t, err)
+		c.MustNotEqualPointer(first, second)
+		cleanupSecond()
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, []string{"second"}, cleaned)
+		cleanupFirst()
+		require.Equal(t, []string{"second", "first"}, cleaned)
+	})
+
+	t.Run("factory returns nil cleanup with constructor error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.Factory(func(args workerArguments) (*worker, func() error, error) {
+			return nil, func() error { return nil }, errors.New("worker error")
+		})
+		c.MustCompile()
+		var newWorker func(jobID string, retry int) (*worker, func() error, error)
+		c.MustExtract(&newWorker)
+		_, cleanup, err := newWorker("job", 0)
+		require.EqualError(t, err, "*di_test.worker: worker error")
+		require.Nil(t, cleanup)
+	})
+
+	t.Run("factory returns constructor error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.Factory(func(args workerArguments) (*worker, error) {
+			return nil, errors.New("worker error")
+		}, di.ProvideParams{Name: "failing"})
+		c.MustCompile()
+		var newWorker func(jobID string, retry int) (*worker, error)
+		c.MustExtractWithName("failing", &newWorker)
+		_, err := newWorker("job", 0)
+		require.EqualError(t, err, "*di_test.worker[failing]: worker error")
+	})
+
+	t.Run("factory dependencies checked on compile", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.Factory(func(args workerArguments, foo *ditest.Foo) *worker { return &worker{} })
+		c.MustCompileError("func(string, int) (*di_test.worker, error): dependency *ditest.Foo not exists in container")
+	})
+
+	t.Run("factory constructor without arguments", func(t *testing.T) {
+		c := NewTestContainer(t)
+		err := c.TryFactory(ditest.NewFoo)
+		require.EqualError(t, err, "The factory constructor must have a parameter structure that embeds di.Arguments, got `func() *ditest.Foo`")
+		require.IsType(t, di.ErrInvalidFactory{}, err)
+	})
+}
+
+// lazyA depends on lazyB lazily.
+type lazyA struct {
+	b di.Lazy[*lazyB]
+}
+
+// lazyB depends on lazyA.
+type lazyB struct {
+	a *lazyA
+}
+
+func TestContainerLazy(t *testing.T) {
+	t.Run("lazy dependency breaks cycle", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func(b di.Lazy[*lazyB]) *lazyA { return &lazyA{b: b} })
+		c.MustProvide(func(a *lazyA) *lazyB { return &lazyB{a: a} })
+		c.MustCompile()
+		var a *lazyA
+		c.MustExtract(&a)
+		b, err := a.b.Get()
+		require.NoError(t, err)
+		c.MustEqualPointer(a, b.a)
+		var extracted *lazyB
+		c.MustExtract(&extracted)
+		c.MustEqualPointer(b, extracted)
+	})
+
+	t.Run("function dependency resolves on call", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var created int
+		c.MustProvide(func() *ditest.Foo {
+			created++
+			return &ditest.Foo{}
+		})
+		c.MustProvide(func(foo func() (*ditest.Foo, error)) *ditest.Bar {
+			require.Equal(t, 0, created)
+			f, err := foo()
+			require.NoError(t, err)
+			return ditest.NewBar(f)
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		require.Equal(t, 1, created)
+	})
+
+	t.Run("lazy dependency returns constructor error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.CreateFooConstructorWithError(errors.New("foo error")))
+		c.MustProvide(func(foo di.Lazy[*ditest.Foo]) *ditest.Bar {
+			_, err := foo.Get()
+			require.EqualError(t, err, "*ditest.Foo: foo error")
+			return &ditest.Bar{}
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+	})
+
+	t.Run("lazy dependency must exist", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(func(foo func() (*ditest.Foo, error)) *ditest.Bar { return &ditest.Bar{} })
+		c.MustCompileError("*ditest.Bar: dependency func() (*ditest.Foo, error) not exists in container")
+	})
+
+	t.Run("provided function type is not lazy", func(t *testing.T) {
+		c := NewTestContainer(t)
+		foo := &ditest.Foo{Name: "provided"}
+		c.MustProvide(func() func() (*ditest.Foo, error) {
+			return func() (*ditest.Foo, error) { return foo, nil }
+		})
+		c.MustProvide(func(fn func() (*ditest.Foo, error)) *ditest.Bar {
+			f, _ := fn()
+			return ditest.NewBar(f)
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(foo, bar.Foo())
+	})
+
+	t.Run("lazy edge visualized as dashed", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(func(foo di.Lazy[*ditest.Foo]) *ditest.Bar { return &ditest.Bar{} })
+		c.MustCompile()
+		var graph *di.Graph
+		c.MustExtract(&graph)
+		require.Contains(t, graph.String(), `[color="#949494",style="dashed"]`)
+	})
+
+	t.Run("lazy dependency created after consumer is cleaned up after it", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleanups []string
+		c.MustProvide(func() (*ditest.Foo, func()) {
+			return &ditest.Foo{}, func() { cleanups = append(cleanups, "foo") }
+		})
+		c.MustProvide(func(foo di.Lazy[*ditest.Foo]) (*ditest.Bar, func()) {
+			return &ditest.Bar{}, func() { cleanups = append(cleanups, "bar") }
+		})
+		c.MustProvide(func(foo func() (*ditest.Foo, error)) (*ditest.Baz, func()) {
+			return &ditest.Baz{}, func() { cleanups = append(cleanups, "baz") }
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		var baz *ditest.Baz
+		c.MustExtract(&baz)
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, []string{"baz", "bar", "foo"}, cleanups)
+	})
+
+	t.Run("lazy not injected by container returns error", func(t *testing.T) {
+		var lazy di.Lazy[*ditest.Foo]
+		foo, err := lazy.Get()
+		require.Nil(t, foo)
+		require.True(t, errors.As(err, &di.ErrLazyNotInjected{}))
+		require.EqualError(t, err, "di.Lazy[*ditest.Foo] is not injected by container")
+	})
+}
+
+func TestContainerReplace(t *testing.T) {
+	t.Run("replaced type keeps interfaces and groups", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		fake := &ditest.Bar{}
+		c.Replace(ditest.CreateBarConstructor(fake), di.As(new(ditest.Fooer)))
+		c.MustCompile()
+		c.MustExtractPtr(fake, new(*ditest.Bar))
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(fake, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+		c.MustEqualPointer(fake, fooers[0])
+	})
+
+	t.Run("replaced type may become prototype", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TryReplace(ditest.NewFoo, di.ProvideParams{IsPrototype: true}))
+		c.MustCompile()
+		var foo1, foo2 *ditest.Foo
+		c.MustExtract(&foo1)
+		c.MustExtract(&foo2)
+		c.MustNotEqualPointer(foo1, foo2)
+	})
+
+	t.Run("replaced named type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideWithName("foo", ditest.NewFoo)
+		fake := &ditest.Foo{Name: "fake"}
+		require.NoError(t, c.TryReplace(ditest.CreateFooConstructor(fake), di.ProvideParams{Name: "foo"}))
+		c.MustCompile()
+		c.MustExtractPtrWithName(fake, "foo", new(*ditest.Foo))
+	})
+
+	t.Run("replaced type not exists", func(t *testing.T) {
+		c := NewTestContainer(t)
+		err := c.TryReplace(ditest.NewFoo)
+		require.EqualError(t, err, "*ditest.Foo: replaced type not exists in container")
+		require.IsType(t, di.ErrReplacedNotFound{}, err)
+	})
+
+	t.Run("replace in compiled container", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustCompile()
+		err := c.TryReplace(ditest.NewFoo)
+		require.EqualError(t, err, "container already compiled")
+		require.IsType(t, di.ErrAlreadyCompiled{}, err)
+	})
+
+	t.Run("supplied value replaced with its interfaces", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.NoError(t, c.TrySupply(&ditest.Bar{}, di.As(new(ditest.Fooer))))
+		require.NoError(t, c.TryReplace(ditest.NewBar))
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		require.NotNil(t, bar.Foo())
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+		c.MustEqualPointer(bar, fooers[0])
+	})
+}
+
+// countingFooer is a decorator of ditest.Fooer.
+type countingFooer struct {
+	ditest.Fooer
+	calls int
+}
+
+func TestContainerDecorate(t *testing.T) {
+	t.Run("decorated singleton passed to every consumer", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		var original *ditest.Bar
+		c.Decorate(func(bar *ditest.Bar, foo *ditest.Foo) *ditest.Bar {
+			original = bar
+			return ditest.NewBar(foo)
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustNotEqualPointer(original, bar)
+		var fooer ditest.Fooer
+		c.MustExtract(&fooer)
+		c.MustEqualPointer(bar, fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+		c.MustEqualPointer(bar, fooers[0])
+	})
+
+	t.Run("decorated interface of singleton is singleton", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		var decorations int
+		c.Decorate(func(fooer ditest.Fooer) ditest.Fooer {
+			decorations++
+			return &countingFooer{Fooer: fooer}
+		})
+		c.MustCompile()
+		var fooer1, fooer2 ditest.Fooer
+		c.MustExtract(&fooer1)
+		c.MustExtract(&fooer2)
+		require.IsType(t, &countingFooer{}, fooer1)
+		c.MustEqualPointer(fooer1, fooer2)
+		require.Equal(t, 1, decorations)
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(bar, fooer1.(*countingFooer).Fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 1)
+		c.MustEqualPointer(fooer1, fooers[0])
+		require.Equal(t, 1, decorations)
+	})
+
+	t.Run("groups receive decorated interface", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar)
+		c.MustProvideWithName("bar", ditest.NewBar, new(ditest.Fooer))
+		c.MustProvide(ditest.NewBaz, di.Interface{Type: new(ditest.Fooer), Name: "baz"})
+		c.Decorate(func(fooer ditest.Fooer) ditest.Fooer {
+			return &countingFooer{Fooer: fooer}
+		}, di.ProvideParams{Name: "bar"})
+		c.MustCompile()
+		var fooer ditest.Fooer
+		c.MustExtractWithName("bar", &fooer)
+		var fooers []ditest.Fooer
+		c.MustExtract(&fooers)
+		require.Len(t, fooers, 2)
+		c.MustEqualPointer(fooer, fooers[0])
+		require.IsType(t, &ditest.Baz{}, fooers[1])
+		var named map[string]ditest.Fooer
+		c.MustExtract(&named)
+		c.MustEqualPointer(fooer, named["bar"])
+	})
+
+	t.Run("ambiguous interface could not be decorated", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustProvide(ditest.NewBar, new(ditest.Fooer))
+		c.MustProvide(ditest.NewBaz, new(ditest.Fooer))
+		err := c.TryDecorate(func(fooer ditest.Fooer) ditest.Fooer {
+			return &countingFooer{Fooer: fooer}
+		})
+		require.EqualError(t, err, "ditest.Fooer: decorated interface have several implementations")
+		require.IsType(t, di.ErrDecoratedAmbiguous{}, err)
+	})
+
+	t.Run("decorators applied in order", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.Decorate(func(foo *ditest.Foo) *ditest.Foo {
+			return &ditest.Foo{Name: foo.Name + "first"}
+		})
+		c.Decorate(func(foo *ditest.Foo) (*ditest.Foo, error) {
+			return &ditest.Foo{Name: foo.Name + " second"}, nil
+		})
+		c.MustCompile()
+		var foo *ditest.Foo
+		c.MustExtract(&foo)
+		require.Equal(t, "first second", foo.Name)
+	})
+
+	t.Run("decorator error returned on extract", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var cleaned bool
+		c.MustProvide(ditest.CreateFooConstructorWithCleanup(func() { cleaned = true }))
+		c.Decorate(func(foo *ditest.Foo) (*ditest.Foo, error) {
+			return nil, errors.New("decorator error")
+		})
+		c.MustCompile()
+		c.MustExtractError(new(*ditest.Foo), "*ditest.Foo: decorator error")
+		require.True(t, cleaned)
+	})
+
+	t.Run("decorated named type", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvideWithName("first", ditest.NewFoo)
+		c.MustProvideWithName("second", ditest.NewFoo)
+		require.NoError(t, c.TryDecorate(func(foo *ditest.Foo) *ditest.Foo {
+			return &ditest.Foo{Name: "decorated"}
+		}, di.ProvideParams{Name: "second"}))
+		c.MustCompile()
+		var foo *ditest.Foo
+		c.MustExtractWithName("second", &foo)
+		require.Equal(t, "decorated", foo.Name)
+		c.MustExtractWithName("first", &foo)
+		require.Equal(t, "", foo.Name)
+	})
+
+	t.Run("decorator dependency added to graph", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.Decorate(func(foo *ditest.Foo, bar *ditest.Bar) *ditest.Foo {
+			return foo
+		})
+		c.MustCompileError("*ditest.Foo: dependency *ditest.Bar not exists in container")
+	})
+
+	t.Run("incorrect decorator", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		require.EqualError(t, c.TryDecorate(func(foo *ditest.Foo) *ditest.Bar { return nil }),
+			"The decorator must be a function like `func(<type>, [dep1, dep2, ...]) (<type>, [cleanup, error])`, got `func(*ditest.Foo) *ditest.Bar`")
+		require.EqualError(t, c.TryDecorate("string"),
+			"The decorator must be a function like `func(<type>, [dep1, dep2, ...]) (<type>, [cleanup, error])`, got `string`")
+	})
+
+	t.Run("decorated type not exists", func(t *testing.T) {
+		c := NewTestContainer(t)
+		err := c.TryDecorate(func(foo *ditest.Foo) *ditest.Foo { return foo })
+		require.EqualError(t, err, "*ditest.Foo: decorated type not exists in container")
+		require.IsType(t, di.ErrDecoratedNotFound{}, err)
+	})
+
+	t.Run("decorate compiled container", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustCompile()
+		err := c.TryDecorate(func(foo *ditest.Foo) *ditest.Foo { return foo })
+		require.EqualError(t, err, "container already compiled")
+		require.IsType(t, di.ErrAlreadyCompiled{}, err)
+	})
+}
+
+// component is a lifecycle test component.
+type component struct {
+	name  string
+	calls *[]string
+	err   error
+}
+
+func (c *component) Start(ctx context.Context) error {
+	*c.calls = append(*c.calls, "start "+c.name)
+	return c.err
+}
+
+func (c *component) Stop(ctx context.Context) error {
+	*c.calls = append(*c.calls, "stop "+c.name)
+	return nil
+}
+
+func TestContainerLifecycle(t *testing.T) {
+	t.Run("container starts components in dependency order and stops in reverse", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls []string
+		c.MustProvide(func(lc di.Lifecycle) *ditest.Foo {
+			lc.Append(di.Hook{
+				OnStart: func(ctx context.Context) error {
+					calls = append(calls, "start foo")
+					return nil
+				},
+				OnStop: func(ctx context.Context) error {
+					calls = append(calls, "stop foo")
+					return nil
+				},
+			})
+			return &ditest.Foo{}
+		})
+		c.MustProvide(func(foo *ditest.Foo) *component {
+			return &component{name: "component", calls: &calls}
+		})
+		c.MustCompile()
+		var comp *component
+		c.MustExtract(&comp)
+		require.NoError(t, c.Start(context.Background()))
+		require.NoError(t, c.Stop(context.Background()))
+		require.Equal(t, []string{"start foo", "start component", "stop component", "stop foo"}, calls)
+	})
+
+	t.Run("container rollbacks started components on start failure", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls []string
+		c.MustProvide(func() *ditest.Foo {
+			return &ditest.Foo{}
+		})
+		c.MustProvide(func(foo *ditest.Foo) *component {
+			return &component{name: "first", calls: &calls}
+		})
+		c.MustProvide(func(comp *component, lc di.Lifecycle) *ditest.Bar {
+			lc.Append(di.Hook{
+				OnStart: func(ctx context.Context) error {
+					calls = append(calls, "start bar")
+					return errors.New("bar failed")
+				},
+			})
+			return &ditest.Bar{}
+		})
+		c.MustCompile()
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		err := c.Start(context.Background())
+		require.EqualError(t, err, "bar failed")
+		require.IsType(t, di.ErrStartFailed{}, err)
+		require.Equal(t, []string{"start first", "start bar", "stop first"}, calls)
+		require.NoError(t, c.Stop(context.Background()))
+		require.Len(t, calls, 3)
+	})
+
+	t.Run("prototypes are not detected as components", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls []string
+		c.MustProvidePrototype(func() *component {
+			return &component{name: "prototype", calls: &calls}
+		})
+		c.MustCompile()
+		var comp *component
+		c.MustExtract(&comp)
+		require.NoError(t, c.Start(context.Background()))
+		require.Empty(t, calls)
+	})
+}
+
+func TestContainerConcurrency(t *testing.T) {
+	t.Run("singleton constructed once on concurrent extraction", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls int32
+		var cleanups int32
+		c.MustProvide(func() (*ditest.Foo, func()) {
+			atomic.AddInt32(&calls, 1)
+			return &ditest.Foo{}, func() { atomic.AddInt32(&cleanups, 1) }
+		})
+		c.MustProvide(ditest.NewBar)
+		c.MustProvidePrototype(ditest.NewBazFromParameters)
+		c.MustCompile()
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 100)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var baz *ditest.Baz
+				errs <- c.Extract(&baz)
+				errs <- c.Invoke(func(bar *ditest.Bar, baz *ditest.Baz) {})
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.NoError(t, c.Cleanup(context.Background()))
+		require.Equal(t, int32(1), calls)
+		require.Equal(t, int32(1), cleanups)
+	})
+}
+
+func TestContainerWarmup(t *testing.T) {
+	t.Run("warmup builds independent singletons concurrently", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var started sync.WaitGroup
+		started.Add(2)
+		waitOther := func() error {
+			started.Done()
+			done := make(chan struct{})
+			go func() { started.Wait(); close(done) }()
+			select {
+			case <-done:
+				return nil
+			case <-time.After(time.Second):
+				return errors.New("constructors are not concurrent")
+			}
+		}
+		foo := &ditest.Foo{}
+		c.MustProvide(func() (*ditest.Foo, error) { return foo, waitOther() })
+		c.MustProvide(func() (*log.Logger, error) { return log.New(ioutil.Discard, "", 0), waitOther() })
+		c.MustProvide(ditest.NewBar)
+		c.MustCompile()
+		require.NoError(t, c.Warmup(context.Background(), 2))
+
+		var bar *ditest.Bar
+		c.MustExtract(&bar)
+		c.MustEqualPointer(foo, bar.Foo())
+	})
+
+	t.Run("warmup stops on the first error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var barCreated bool
+		c.MustProvide(ditest.CreateFooConstructorWithError(errors.New("internal error")))
+		c.MustProvide(func(foo *ditest.Foo) *ditest.Bar {
+			barCreated = true
+			return &ditest.Bar{}
+		})
+		c.MustCompile()
+		require.EqualError(t, c.Warmup(context.Background(), 0), "*ditest.Foo: internal error")
+		require.False(t, barCreated)
+	})
+
+	t.Run("warmup does not start constructors of the layer after the first error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var calls int32
+		fail := func() error {
+			atomic.AddInt32(&calls, 1)
+			return errors.New("internal error")
+		}
+		c.MustProvide(func() (*ditest.Foo, error) { return nil, fail() })
+		c.MustProvide(func() (*log.Logger, error) { return nil, fail() })
+		c.MustProvide(func() (*http.ServeMux, error) { return nil, fail() })
+		c.MustCompile()
+		require.Error(t, c.Warmup(context.Background(), 1))
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("warmup limits running constructors", func(t *testing.T) {
+		c := NewTestContainer(t)
+		var running, max int32
+		track := func() {
+			if n := atomic.AddInt32(&running, 1); n > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}
+		c.MustProvide(func() *ditest.Foo { track(); return &ditest.Foo{} })
+		c.MustProvide(func() *log.Logger { track(); return log.New(ioutil.Discard, "", 0) })
+		c.MustProvide(func() *http.ServeMux { track(); return http.NewServeMux() })
+		c.MustCompile()
+		require.NoError(t, c.Warmup(context.Background(), 1))
+		require.Equal(t, int32(1), atomic.LoadInt32(&max))
+	})
+
+	t.Run("warmup respects context", func(t *testing.T) {
+		c := NewTestContainer(t)
+		c.MustProvide(ditest.NewFoo)
+		c.MustCompile()
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.Equal(t, context.Canceled, c.Warmup(ctx, 1))
+	})
+
+	t.Run("warmup before compile cause error", func(t *testing.T) {
+		c := NewTestContainer(t)
+		require.EqualError(t, c.Warmup(context.Background(), 1), "container not compiled")
+	})
 }
 
 func TestContainer_GraphVisualizing(t *testing.T) {
@@ -565,6 +2106,7 @@ func TestContainer_GraphVisualizing(t *testing.T) {
 		bgcolor="#E8E8E8";color="lightgrey";fontcolor="#46494C";fontname="COURIER";label="";style="rounded";
 		n9[color="#46494C",fontcolor="white",fontname="COURIER",label="*di.Graph",shape="box",style="filled"];
 		n10[color="#46494C",fontcolor="white",fontname="COURIER",label="di.Interactor",shape="box",style="filled"];
+		n11[color="#46494C",fontcolor="white",fontname="COURIER",label="di.Lifecycle",shape="box",style="filled"];
 		
 	}subgraph cluster_s2 {
 		ID = "cluster_s2";
@@ -1,22 +1,348 @@
 package di
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"strings"
 
-// ErrParameterProvideFailed
+	"github.com/defval/inject/v2/di/internal/graphkv"
+)
+
+// ErrInvalidConstructor is returned when the constructor is not a function or has an unsupported signature.
+type ErrInvalidConstructor struct {
+	ctor string
+}
+
+func (e ErrInvalidConstructor) Error() string {
+	return fmt.Sprintf("The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `%s`", e.ctor)
+}
+
+// ErrInvalidFactory is returned when the factory constructor has no arguments structure.
+type ErrInvalidFactory struct {
+	ctor string
+}
+
+func (e ErrInvalidFactory) Error() string {
+	return fmt.Sprintf("The factory constructor must have a parameter structure that embeds di.Arguments, got `%s`", e.ctor)
+}
+
+// ErrInvalidSupply is returned when the supplied value is nil.
+type ErrInvalidSupply struct{}
+
+func (e ErrInvalidSupply) Error() string {
+	return "The supplied value must be not nil"
+}
+
+// ErrInvalidDecorator is returned when the decorator is not a function or has an unsupported signature.
+type ErrInvalidDecorator struct {
+	decorator string
+}
+
+func (e ErrInvalidDecorator) Error() string {
+	return fmt.Sprintf("The decorator must be a function like `func(<type>, [dep1, dep2, ...]) (<type>, [cleanup, error])`, got `%s`", e.decorator)
+}
+
+// ErrDecoratedNotFound is returned when the decorated type does not exist in container.
+type ErrDecoratedNotFound struct {
+	k key
+}
+
+func (e ErrDecoratedNotFound) Error() string {
+	return fmt.Sprintf("%s: decorated type not exists in container", e.k)
+}
+
+// ErrDecoratedAmbiguous is returned when the decorated interface has several implementations.
+type ErrDecoratedAmbiguous struct {
+	k key
+}
+
+func (e ErrDecoratedAmbiguous) Error() string {
+	return fmt.Sprintf("%s: decorated interface have several implementations", e.k)
+}
+
+// ErrReplacedNotFound is returned when the replaced type does not exist in container.
+type ErrReplacedNotFound struct {
+	k key
+}
+
+func (e ErrReplacedNotFound) Error() string {
+	return fmt.Sprintf("%s: replaced type not exists in container", e.k)
+}
+
+// ErrAlreadyCompiled is returned when the container could not be changed after compilation.
+type ErrAlreadyCompiled struct{}
+
+func (e ErrAlreadyCompiled) Error() string {
+	return "container already compiled"
+}
+
+// ErrLazyNotInjected is returned by Get() of Lazy that is not created by container.
+type ErrLazyNotInjected struct {
+	typ reflect.Type
+}
+
+func (e ErrLazyNotInjected) Error() string {
+	return fmt.Sprintf("di.Lazy[%s] is not injected by container", e.typ)
+}
+
+// ErrNotCompiled is returned when the container is used before compilation.
+type ErrNotCompiled struct{}
+
+func (e ErrNotCompiled) Error() string {
+	return "container not compiled"
+}
+
+// ErrParentNotCompiled is returned when the scope is compiled before its parent.
+type ErrParentNotCompiled struct{}
+
+func (e ErrParentNotCompiled) Error() string {
+	return "parent container not compiled"
+}
+
+// ErrProviderAlreadyExists is returned when a type with the same name already provided.
+type ErrProviderAlreadyExists struct {
+	k key
+}
+
+func (e ErrProviderAlreadyExists) Error() string {
+	return fmt.Sprintf("The `%s` type already exists in container", e.k)
+}
+
+// ErrPrimaryAlreadyExists is returned when the interface already has a primary implementation.
+type ErrPrimaryAlreadyExists struct {
+	k       key
+	primary key
+}
+
+func (e ErrPrimaryAlreadyExists) Error() string {
+	return fmt.Sprintf("The `%s` interface already has primary implementation `%s`", e.k, e.primary)
+}
+
+// ErrInvalidInterface is returned when As() argument is not a pointer to an interface.
+type ErrInvalidInterface struct {
+	typ reflect.Type
+}
+
+func (e ErrInvalidInterface) Error() string {
+	return fmt.Sprintf("%v: not a pointer to interface", e.typ)
+}
+
+// ErrNotImplementInterface is returned when the provided type does not implement the interface from As().
+type ErrNotImplementInterface struct {
+	k     key
+	iface reflect.Type
+}
+
+func (e ErrNotImplementInterface) Error() string {
+	return fmt.Sprintf("%s not implement %s", e.k, e.iface)
+}
+
+// ErrDependencyNotFound is returned on compile when the provider dependency not exists in container.
+type ErrDependencyNotFound struct {
+	consumer    key
+	param       parameter
+	suggestions []key
+}
+
+func (e ErrDependencyNotFound) Error() string {
+	return fmt.Sprintf("%s: dependency %s not exists in container%s", e.consumer, e.param, suggestionString(e.suggestions))
+}
+
+// ErrDependencyAmbiguous is returned on compile when the provider depends on an interface that has several
+// implementations.
+type ErrDependencyAmbiguous struct {
+	consumer key
+	param    parameter
+}
+
+func (e ErrDependencyAmbiguous) Error() string {
+	return fmt.Sprintf("%s: dependency %s have several implementations", e.consumer, e.param)
+}
+
+// ErrInvalidTag is returned on compile when the `di` tag of the parameter field could not be parsed.
+type ErrInvalidTag struct {
+	consumer key
+	field    string
+	tag      string
+}
+
+func (e ErrInvalidTag) Error() string {
+	return fmt.Sprintf("%s: incorrect di tag `%s` of field %s", e.consumer, e.tag, e.field)
+}
+
+// ErrDependencyCycle is returned on compile when the dependency graph has a cycle. The cycle path is ordered from
+// consumer to dependency, the first and the last types are equal.
+type ErrDependencyCycle struct {
+	path []key
+}
+
+// newErrDependencyCycle creates cycle error from graph cycle. The graph edges are directed from dependency to
+// consumer, so the cycle is reversed.
+func newErrDependencyCycle(cycle []graphkv.Key) ErrDependencyCycle {
+	var path []key
+	for i := len(cycle) - 1; i >= 0; i-- {
+		path = append(path, cycle[i].(key))
+	}
+	return ErrDependencyCycle{path: path}
+}
+
+// Path returns type names of the cycle as they are represented in the DOT graph.
+func (e ErrDependencyCycle) Path() []string {
+	var path []string
+	for _, k := range e.path {
+		path = append(path, k.String())
+	}
+	return path
+}
+
+func (e ErrDependencyCycle) Error() string {
+	return fmt.Sprintf("the graph cannot be cyclic: %s", strings.Join(e.Path(), " -> "))
+}
+
+// ErrCompileFailed is returned on compile and contains every error found in the dependency graph.
+type ErrCompileFailed struct {
+	errs []error
+}
+
+// Errors returns all compile errors.
+func (e ErrCompileFailed) Errors() []error {
+	return e.errs
+}
+
+// Unwrap returns all compile errors for errors.Is() and errors.As().
+func (e ErrCompileFailed) Unwrap() []error {
+	return e.errs
+}
+
+func (e ErrCompileFailed) Error() string {
+	return joinErrors("compile", e.errs)
+}
+
+// ErrCleanupFailed is returned on cleanup and contains every destructor error.
+type ErrCleanupFailed struct {
+	errs []error
+}
+
+// Errors returns all cleanup errors.
+func (e ErrCleanupFailed) Errors() []error {
+	return e.errs
+}
+
+// Unwrap returns all cleanup errors for errors.Is() and errors.As().
+func (e ErrCleanupFailed) Unwrap() []error {
+	return e.errs
+}
+
+func (e ErrCleanupFailed) Error() string {
+	return joinErrors("cleanup", e.errs)
+}
+
+// ErrStartFailed is returned when some start hook fails. It contains the start error and errors of stop hooks
+// that were called for rollback.
+type ErrStartFailed struct {
+	errs []error
+}
+
+// Errors returns start error and rollback errors.
+func (e ErrStartFailed) Errors() []error {
+	return e.errs
+}
+
+// Unwrap returns start error and rollback errors for errors.Is() and errors.As().
+func (e ErrStartFailed) Unwrap() []error {
+	return e.errs
+}
+
+func (e ErrStartFailed) Error() string {
+	return joinErrors("start", e.errs)
+}
+
+// ErrStopFailed is returned on stop and contains every stop hook error.
+type ErrStopFailed struct {
+	errs []error
+}
+
+// Errors returns all stop errors.
+func (e ErrStopFailed) Errors() []error {
+	return e.errs
+}
+
+// Unwrap returns all stop errors for errors.Is() and errors.As().
+func (e ErrStopFailed) Unwrap() []error {
+	return e.errs
+}
+
+func (e ErrStopFailed) Error() string {
+	return joinErrors("stop", e.errs)
+}
+
+// joinErrors represents errors of the operation as multiline string. Single error is represented as is.
+func joinErrors(operation string, errs []error) string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	var lines []string
+	for _, err := range errs {
+		lines = append(lines, "\t"+err.Error())
+	}
+	return fmt.Sprintf("%s failed with %d errors:\n%s", operation, len(errs), strings.Join(lines, "\n"))
+}
+
+// ErrParameterProvideFailed is returned when the constructor of some type in the dependency chain returns an error.
 type ErrParameterProvideFailed struct {
-	k   key
-	err error
+	k     key
+	err   error
+	chain []key
+}
+
+// Chain returns the dependency chain from the resolved type to the failed type.
+func (e ErrParameterProvideFailed) Chain() []string {
+	return chainString(e.chain, e.k)
+}
+
+// Unwrap returns the constructor error.
+func (e ErrParameterProvideFailed) Unwrap() error {
+	return e.err
 }
 
 func (e ErrParameterProvideFailed) Error() string {
-	return fmt.Sprintf("%s: %s", e.k, e.err)
+	return fmt.Sprintf("%s: %s", strings.Join(e.Chain(), " -> "), e.err)
 }
 
-// ErrParameterProviderNotFound
+// ErrParameterProviderNotFound is returned when some type in the dependency chain not exists in container.
 type ErrParameterProviderNotFound struct {
-	param parameter
+	param       parameter
+	chain       []key
+	suggestions []key
+}
+
+// Chain returns the dependency chain from the resolved type to the missing type.
+func (e ErrParameterProviderNotFound) Chain() []string {
+	return chainString(e.chain, e.param)
 }
 
 func (e ErrParameterProviderNotFound) Error() string {
-	return fmt.Sprintf("%s: not exists in container", e.param)
+	return fmt.Sprintf("%s: not exists in container%s", strings.Join(e.Chain(), " -> "), suggestionString(e.suggestions))
+}
+
+// withConsumer adds consumer at the beginning of the dependency chain of resolve error.
+func withConsumer(err error, consumer key) error {
+	switch e := err.(type) {
+	case ErrParameterProvideFailed:
+		e.chain = append([]key{consumer}, e.chain...)
+		return e
+	case ErrParameterProviderNotFound:
+		e.chain = append([]key{consumer}, e.chain...)
+		return e
+	}
+	return err
+}
+
+// chainString
+func chainString(chain []key, last fmt.Stringer) []string {
+	var result []string
+	for _, k := range chain {
+		result = append(result, k.String())
+	}
+	return append(result, last.String())
 }
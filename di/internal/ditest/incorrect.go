@@ -11,6 +11,6 @@ func ConstructorWithManyResults() (*Foo, *Bar, error) {
 }
 
 // ConstructorWithIncorrectResultError
-func ConstructorWithIncorrectResultError() (*Foo, *Bar) {
-	return &Foo{}, &Bar{}
+func ConstructorWithIncorrectResultError() (*Foo, error, *Bar) {
+	return &Foo{}, nil, &Bar{}
 }
@@ -1,6 +1,9 @@
 package graphkv
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // ErrKeyAlreadyExists
 type ErrKeyAlreadyExists struct {
@@ -20,3 +23,21 @@ type ErrNodeNotExists struct {
 func (e ErrNodeNotExists) Error() string {
 	return fmt.Sprintf("%s not exists", e.Key)
 }
+
+// ErrCycle is returned when the graph has a cycle. Path contains the keys of the cycle in the edge direction.
+type ErrCycle struct {
+	Path []Key
+}
+
+func (e ErrCycle) Error() string {
+	var keys []string
+	for _, key := range e.Path {
+		keys = append(keys, fmt.Sprintf("%v", key))
+	}
+	return fmt.Sprintf("%s: %s", ErrCyclicGraph, strings.Join(keys, " -> "))
+}
+
+// Is reports that ErrCycle is ErrCyclicGraph.
+func (e ErrCycle) Is(target error) bool {
+	return target == ErrCyclicGraph
+}
@@ -14,6 +14,7 @@ type Node struct {
 type Graph struct {
 	dag    *directedGraph
 	values map[Key]interface{}
+	lazy   map[Key][]Key // edges that are not dependencies of construction
 }
 
 // New
@@ -25,6 +26,7 @@ func New() *Graph {
 	return &Graph{
 		dag:    newDirectedGraph(),
 		values: map[Key]interface{}{},
+		lazy:   map[Key][]Key{},
 	}
 }
 
@@ -44,11 +46,41 @@ func (g *Graph) Add(key Key, value interface{}) {
 	g.values[key] = value
 }
 
+// Remove removes node without edges.
+func (g *Graph) Remove(key Key) {
+	g.dag.RemoveNode(key)
+	delete(g.values, key)
+}
+
 // Edge
 func (g *Graph) Edge(from Key, to Key) {
 	g.dag.AddEdge(from, to)
 }
 
+// LazyEdge adds edge that is visualized but not checked for cycles and not used for sorting.
+func (g *Graph) LazyEdge(from Key, to Key) {
+	g.lazy[from] = append(g.lazy[from], to)
+}
+
+// Consumers returns all nodes that depend on the node directly or through other nodes, lazy edges included.
+func (g *Graph) Consumers(key Key) map[Key]bool {
+	consumers := map[Key]bool{}
+	queue := []Key{key}
+	for len(queue) != 0 {
+		node := queue[0]
+		queue = queue[1:]
+		next := append(append([]Key{}, g.dag.OutgoingEdges(node)...), g.lazy[node]...)
+		for _, consumer := range next {
+			if consumers[consumer] {
+				continue
+			}
+			consumers[consumer] = true
+			queue = append(queue, consumer)
+		}
+	}
+	return consumers
+}
+
 // Exists
 func (g *Graph) Exists(key Key) bool {
 	return g.dag.NodeExists(key)
@@ -63,13 +95,24 @@ func (g *Graph) Nodes() []Node {
 	return nodes
 }
 
-// CheckCycles
+// CheckCycles returns ErrCycle with the first found cycle.
 func (g *Graph) CheckCycles() error {
 	_, err := g.dag.DFSSort()
-	return err // todo: errors
+	return err
+}
+
+// Cycles returns all cycles found in graph.
+func (g *Graph) Cycles() [][]Key {
+	return g.dag.Cycles()
+}
+
+// Layers splits graph nodes into layers. Nodes of each layer depend only on nodes from previous layers. Width
+// limits the number of nodes in the layer.
+func (g *Graph) Layers(width int) ([][]Key, error) {
+	return g.dag.CoffmanGrahamSort(width)
 }
 
 // DOTGraph
 func (g *Graph) DOTGraph() *dot.Graph {
-	return g.dag.DOTGraph()
+	return g.dag.DOTGraph(g.lazy)
 }
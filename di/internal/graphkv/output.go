@@ -14,8 +14,8 @@ type NodeVisualizer interface {
 }
 
 // DOTGraph returns a textual representation of the graph in the DOT graph
-// description language.
-func (g *directedGraph) DOTGraph() *dot.Graph {
+// description language. Lazy edges are dashed.
+func (g *directedGraph) DOTGraph(lazy map[Key][]Key) *dot.Graph {
 	root := dot.NewGraph(dot.Directed)
 	root.Attr("splines", "ortho")
 
@@ -24,7 +24,7 @@ func (g *directedGraph) DOTGraph() *dot.Graph {
 	for _, node := range g.Nodes() {
 		nv := node.(NodeVisualizer)
 
-		if !g.HasOutgoingEdges(node) && !nv.IsAlwaysVisible() {
+		if !g.HasOutgoingEdges(node) && len(lazy[node]) == 0 && !nv.IsAlwaysVisible() {
 			continue
 		}
 
@@ -47,6 +47,11 @@ func (g *directedGraph) DOTGraph() *dot.Graph {
 				root.Edge(fromItem, toItem).Attr("color", "#949494")
 			}
 		}
+		for _, toNode := range lazy[fromNode] {
+			if toItem, ok := itemsByNode[toNode]; ok {
+				root.Edge(fromItem, toItem).Attr("color", "#949494").Attr("style", "dashed")
+			}
+		}
 	}
 
 	return root
@@ -16,6 +16,7 @@ type DFSSorter struct {
 	sorted     []Key
 	visiting   map[Key]bool
 	discovered map[Key]bool
+	path       []Key
 }
 
 // NewDFSSorter returns a new DFS sorter.
@@ -29,6 +30,7 @@ func (s *DFSSorter) init() {
 	s.sorted = make([]Key, 0, s.graph.NodeCount())
 	s.visiting = make(map[Key]bool)
 	s.discovered = make(map[Key]bool, s.graph.NodeCount())
+	s.path = make([]Key, 0)
 }
 
 // Sort returns the sorted nodes.
@@ -60,11 +62,12 @@ func (s *DFSSorter) visit(node Key) error {
 	}
 	// > if n has a temporary mark then stop (not a DAG)
 	if visiting, ok := s.visiting[node]; ok && visiting {
-		return ErrCyclicGraph
+		return ErrCycle{Path: cyclePath(s.path, node)}
 	}
 
 	// > mark n temporarily
 	s.visiting[node] = true
+	s.path = append(s.path, node)
 
 	// > for each node m with an edge from n to m do
 	for _, outgoing := range s.graph.OutgoingEdges(node) {
@@ -75,6 +78,7 @@ func (s *DFSSorter) visit(node Key) error {
 
 	s.discovered[node] = true
 	delete(s.visiting, node)
+	s.path = s.path[:len(s.path)-1]
 
 	s.sorted = append(s.sorted, node)
 	return nil
@@ -87,6 +91,49 @@ func (g *directedGraph) DFSSort() ([]Key, error) {
 	return sorter.Sort()
 }
 
+// Cycles returns cycles of the graph. Each back edge found by depth-first search produces one cycle. The first
+// and the last keys of the cycle are equal.
+func (g *directedGraph) Cycles() [][]Key {
+	var cycles [][]Key
+	var path []Key
+	visiting := make(map[Key]bool)
+	discovered := make(map[Key]bool, g.NodeCount())
+	var visit func(node Key)
+	visit = func(node Key) {
+		if discovered[node] {
+			return
+		}
+		visiting[node] = true
+		path = append(path, node)
+		for _, outgoing := range g.OutgoingEdges(node) {
+			if visiting[outgoing] {
+				cycles = append(cycles, cyclePath(path, outgoing))
+				continue
+			}
+			visit(outgoing)
+		}
+		path = path[:len(path)-1]
+		delete(visiting, node)
+		discovered[node] = true
+	}
+	for _, node := range g.Nodes() {
+		visit(node)
+	}
+	return cycles
+}
+
+// cyclePath cuts the cycle that ends with node from the depth-first search path.
+func cyclePath(path []Key, node Key) []Key {
+	for i := range path {
+		if path[i] == node {
+			cycle := make([]Key, 0, len(path)-i+1)
+			cycle = append(cycle, path[i:]...)
+			return append(cycle, node)
+		}
+	}
+	return []Key{node}
+}
+
 // Errors relating to the CoffmanGrahamSorter.
 var (
 	ErrDependencyOrder = errors.New("the topological dependency order is incorrect")
@@ -1,6 +1,7 @@
 package graphkv
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,10 +33,27 @@ func TestDFSSorterCyclic(t *testing.T) {
 
 	sorted, err := graph.DFSSort()
 
-	assert.EqualError(t, err, ErrCyclicGraph.Error(), "graph.DFSSort() error should be ErrCyclicGraph")
+	assert.True(t, errors.Is(err, ErrCyclicGraph), "graph.DFSSort() error should be ErrCyclicGraph")
+	assert.EqualError(t, err, "the graph cannot be cyclic: 0 -> 1 -> 0")
 	assert.Nil(t, sorted, "graph.DFSSort() nodes should be nil")
 }
 
+func TestDirectedGraphCycles(t *testing.T) {
+	graph := newDirectedGraph()
+	graph.AddNodes(0, 1, 2, 3, 4, 5)
+	graph.AddEdge(0, 1)
+	graph.AddEdge(1, 2)
+	graph.AddEdge(2, 0)
+	graph.AddEdge(3, 4)
+	graph.AddEdge(4, 3)
+	graph.AddEdge(4, 5)
+
+	assert.Equal(t, [][]Key{
+		{0, 1, 2, 0},
+		{3, 4, 3},
+	}, graph.Cycles(), "graph.Cycles() should equal [[0, 1, 2, 0], [3, 4, 3]]")
+}
+
 func TestCoffmanGrahamSorter(t *testing.T) {
 	graph := newDirectedGraph()
 
@@ -81,6 +99,6 @@ func TestCoffmanGrahamSorterCyclic(t *testing.T) {
 
 	sorted, err := graph.CoffmanGrahamSort(2)
 
-	assert.EqualError(t, err, ErrCyclicGraph.Error(), "graph.CoffmanGrahamSort(2) error should be ErrCyclicGraph")
+	assert.True(t, errors.Is(err, ErrCyclicGraph), "graph.CoffmanGrahamSort(2) error should be ErrCyclicGraph")
 	assert.Nil(t, sorted, "graph.CoffmanGrahamSort(2) nodes should be nil")
 }
@@ -5,6 +5,12 @@ import (
 	"reflect"
 )
 
+// IsInterfacePtr
+func IsInterfacePtr(value interface{}) bool {
+	typ := reflect.TypeOf(value)
+	return typ != nil && typ.Kind() == reflect.Ptr && typ.Elem().Kind() == reflect.Interface
+}
+
 // InspectInterfacePtr
 func InspectInterfacePtr(iface interface{}) *Interface {
 	typ := reflect.TypeOf(iface)
@@ -9,9 +9,12 @@ func IsError(typ reflect.Type) bool {
 	return typ.Implements(errorInterface)
 }
 
-// IsCleanup
+// IsCleanup checks that type is func() or func() error.
 func IsCleanup(typ reflect.Type) bool {
-	return typ.Kind() == reflect.Func && typ.NumIn() == 0 && typ.NumOut() == 0
+	if typ.Kind() != reflect.Func || typ.NumIn() != 0 {
+		return false
+	}
+	return typ.NumOut() == 0 || typ.NumOut() == 1 && typ.Out(0) == errorInterface
 }
 
 // IsPtr
@@ -1,6 +1,7 @@
 package di
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -48,11 +49,11 @@ func newInvoker(fn interface{}) (*invoker, error) {
 	}, nil
 }
 
-func (i *invoker) Invoke(c *Container) error {
+func (i *invoker) Invoke(ctx context.Context, c *Container) error {
 	plist := i.parameters()
-	values, err := plist.Resolve(c)
+	values, err := plist.Resolve(ctx, c)
 	if err != nil {
-		return fmt.Errorf("could not resolve invoke parameters: %s", err)
+		return fmt.Errorf("could not resolve invoke parameters: %w", err)
 	}
 	results := i.fn.Call(values)
 	if len(results) == 0 {
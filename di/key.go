@@ -24,7 +24,7 @@ func (k key) String() string {
 
 // IsAlwaysVisible
 func (k key) IsAlwaysVisible() bool {
-	return k.typ == ptConstructor
+	return k.typ == ptConstructor || k.typ == ptSupplied
 }
 
 // Package
@@ -58,5 +58,11 @@ func (k key) Visualize(node *dot.Node) {
 	case ptEmbedParameter:
 		node.Attr("shape", "box")
 		node.Attr("color", "#E5984B")
+	case ptResults:
+		node.Attr("shape", "box")
+		node.Attr("color", "#7D8491")
+	case ptSupplied:
+		node.Attr("shape", "note")
+		node.Attr("color", "#3FA34D")
 	}
 }
@@ -0,0 +1,87 @@
+package di
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/defval/inject/v2/di/internal/reflection"
+)
+
+// Lazy is a dependency that resolves on Get() call instead of consumer construction. Declare it as constructor
+// parameter to break dependency cycle or to create rarely used heavy dependency only when it is needed. The same
+// behavior has parameter of type func() (T, error). Get() must not be called inside the consumer constructor if
+// the dependency depends on the consumer.
+//
+//   func NewCommand(db di.Lazy[*sql.DB]) *Command {
+//     return &Command{db: db}
+//   }
+//
+//   db, err := c.db.Get()
+type Lazy[T any] struct {
+	resolve func() (reflect.Value, error)
+}
+
+// Get resolves value of the dependency. Singleton is built once, prototype is built on every call. Get() of
+// Lazy that is not injected by container returns ErrLazyNotInjected.
+func (l Lazy[T]) Get() (T, error) {
+	var result T
+	if l.resolve == nil {
+		return result, ErrLazyNotInjected{typ: l.lazyType()}
+	}
+	value, err := l.resolve()
+	if err != nil {
+		return result, err
+	}
+	return value.Interface().(T), nil
+}
+
+func (l *Lazy[T]) setResolver(resolve func() (reflect.Value, error)) {
+	l.resolve = resolve
+}
+
+func (l Lazy[T]) lazyType() reflect.Type {
+	return reflect.TypeOf(new(T)).Elem()
+}
+
+// lazyParameter is implemented by Lazy.
+type lazyParameter interface {
+	setResolver(resolve func() (reflect.Value, error))
+	lazyType() reflect.Type
+}
+
+// lazyParameterInterface
+var lazyParameterInterface = reflect.TypeOf(new(lazyParameter)).Elem()
+
+// lazyOf returns lazy dependency of parameter: T of di.Lazy[T] or func() (T, error).
+func (p parameter) lazyOf() (parameter, bool) {
+	switch {
+	case p.res.Kind() == reflect.Struct && reflect.PtrTo(p.res).Implements(lazyParameterInterface):
+		lazy := reflect.New(p.res).Interface().(lazyParameter)
+		return parameter{name: p.name, res: lazy.lazyType()}, true
+	case p.res.Kind() == reflect.Func && p.res.NumIn() == 0 && p.res.NumOut() == 2 && reflection.IsError(p.res.Out(1)):
+		return parameter{name: p.name, res: p.res.Out(0)}, true
+	}
+	return parameter{}, false
+}
+
+// lazyValue creates value of lazy parameter that resolves dependency in container on call. Dependency is resolved
+// with background context because the call may happen after construction.
+func (p parameter) lazyValue(c *Container, dependency parameter) reflect.Value {
+	resolve := func() (reflect.Value, error) {
+		return dependency.ResolveValue(context.Background(), c)
+	}
+	if p.res.Kind() == reflect.Func {
+		return reflect.MakeFunc(p.res, func(args []reflect.Value) []reflect.Value {
+			errValue := reflect.New(p.res.Out(1)).Elem()
+			value, err := resolve()
+			if err != nil {
+				errValue.Set(reflect.ValueOf(err))
+				return []reflect.Value{reflect.New(dependency.res).Elem(), errValue}
+			}
+			return []reflect.Value{value, errValue}
+		})
+	}
+	lazy := reflect.New(p.res)
+	lazy.Interface().(lazyParameter).setResolver(resolve)
+	return lazy.Elem()
+}
@@ -0,0 +1,126 @@
+package di
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// Hook is a pair of functions that start and stop a component.
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle registers component hooks. Declare it as constructor parameter to append hooks of the created
+// component.
+//
+//   func NewServer(lc di.Lifecycle) *http.Server {
+//     server := &http.Server{}
+//     lc.Append(di.Hook{
+//       OnStart: func(ctx context.Context) error { go server.ListenAndServe(); return nil },
+//       OnStop:  server.Shutdown,
+//     })
+//     return server
+//   }
+type Lifecycle interface {
+	Append(hook Hook)
+}
+
+// Starter is a component that starts with container. Singletons implementing it are detected automatically.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is a component that stops with container. Singletons implementing it are detected automatically.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// lifecycle stores hooks in order of component creation. Component always created after its dependencies, so
+// hooks are ordered by dependencies.
+type lifecycle struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started int
+}
+
+// newLifecycle
+func newLifecycle() *lifecycle {
+	return &lifecycle{}
+}
+
+// Append
+func (l *lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	l.hooks = append(l.hooks, hook)
+	l.mu.Unlock()
+}
+
+// detect appends hook for the value that implements Starter or Stopper.
+func (l *lifecycle) detect(value reflect.Value) {
+	if !value.IsValid() || !value.CanInterface() {
+		return
+	}
+	var hook Hook
+	switch component := value.Interface().(type) {
+	case *Container:
+		// container is not a component
+		return
+	case Starter:
+		hook.OnStart = component.Start
+	}
+	if component, ok := value.Interface().(Stopper); ok {
+		hook.OnStop = component.Stop
+	}
+	if hook.OnStart != nil || hook.OnStop != nil {
+		l.Append(hook)
+	}
+}
+
+// start runs start hooks that are not started yet. If some hook fails, already started hooks are stopped in
+// reverse order.
+func (l *lifecycle) start(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.started == len(l.hooks) {
+			l.mu.Unlock()
+			return nil
+		}
+		hook := l.hooks[l.started]
+		l.mu.Unlock()
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				errs := []error{err}
+				if err := l.stop(ctx); err != nil {
+					errs = append(errs, err.(ErrStopFailed).errs...)
+				}
+				return ErrStartFailed{errs: errs}
+			}
+		}
+		l.mu.Lock()
+		l.started++
+		l.mu.Unlock()
+	}
+}
+
+// stop runs stop hooks of started components in reverse order.
+func (l *lifecycle) stop(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks[:l.started]
+	l.started = 0
+	l.mu.Unlock()
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if hooks[i].OnStop == nil {
+			continue
+		}
+		if err := hooks[i].OnStop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return ErrStopFailed{errs: errs}
+	}
+	return nil
+}
@@ -12,18 +12,37 @@ func (o provideOption) apply(params *ProvideParams) {
 }
 
 // ProvideParams is a `Provide()` method options. Name is a unique identifier of type instance. Provider is a constructor
-// function. Interfaces is a interface that implements a provider result type.
+// function. Interfaces is a interface that implements a provider result type. Priority orders instance in interface
+// groups, lower first. Primary marks instance as the default implementation of its interfaces.
 type ProvideParams struct {
 	Name        string
 	Interfaces  []interface{}
 	Parameters  ParameterBag
 	IsPrototype bool
+	Priority    int
+	Primary     bool
 }
 
 func (p ProvideParams) apply(params *ProvideParams) {
 	*params = p
 }
 
+// Interface is an interface binding with name and group. Use it instead of pointer to interface in
+// ProvideParams.Interfaces to set the binding name and the group name.
+//
+//   c.Provide(NewAdminHandler, di.As(di.Interface{Type: new(http.Handler), Group: "admin"}))
+//
+//   var handlers []http.Handler
+//   c.Extract(&handlers, di.ExtractParams{Name: "admin"})
+type Interface struct {
+	// Type is a pointer to interface.
+	Type interface{}
+	// Name is a name of the interface binding. Provider name by default.
+	Name string
+	// Group is a name of the interface group. Members of named group are not members of the default group.
+	Group string
+}
+
 // As
 func As(interfaces ...interface{}) ProvideOption {
 	return provideOption(func(params *ProvideParams) {
@@ -31,6 +50,26 @@ func As(interfaces ...interface{}) ProvideOption {
 	})
 }
 
+// Priority sets order of instance in interface groups. Instances with lower priority go first, instances with the
+// same priority keep the order of providing.
+//
+//   c.Provide(NewRecoveryMiddleware, di.As(new(Middleware)), di.Priority(-10))
+func Priority(priority int) ProvideOption {
+	return provideOption(func(params *ProvideParams) {
+		params.Priority = priority
+	})
+}
+
+// Primary marks instance as the default implementation of its interfaces. Groups still contain all implementations.
+//
+//   c.Provide(NewPostgresStorage, di.As(new(Storage)), di.Primary())
+//   c.Provide(NewMemoryStorage, di.As(new(Storage)))
+func Primary() ProvideOption {
+	return provideOption(func(params *ProvideParams) {
+		params.Primary = true
+	})
+}
+
 // InvokeParams is a invoke parameters.
 type InvokeParams struct{}
 
@@ -1,7 +0,0 @@
-package di
-
-import "fmt"
-
-func panicf(format string, a ...interface{}) {
-	panic(fmt.Sprintf(format, a...))
-}
@@ -1,6 +1,7 @@
 package di
 
 import (
+	"context"
 	"reflect"
 )
 
@@ -23,47 +24,112 @@ func (p parameter) String() string {
 
 // ResolveProvider resolves parameter provider
 func (p parameter) ResolveProvider(c *Container) (internalProvider, bool) {
-	for _, pt := range providerLookupSequence {
-		k := key{
-			name: p.name,
-			res:  p.res,
-			typ:  pt,
-		}
-		if !c.graph.Exists(k) {
-			continue
+	provider, _, exists := p.resolveProvider(c)
+	return provider, exists
+}
+
+// resolveProvider resolves parameter provider in the container and its parents. It returns container that owns
+// the provider.
+func (p parameter) resolveProvider(c *Container) (internalProvider, *Container, bool) {
+	for scope := c; scope != nil; scope = scope.parent {
+		for _, pt := range providerLookupSequence {
+			k := key{
+				name: p.name,
+				res:  p.res,
+				typ:  pt,
+			}
+			if !scope.graph.Exists(k) {
+				continue
+			}
+			node := scope.graph.Get(k)
+			return node.Value.(internalProvider), scope, true
 		}
-		node := c.graph.Get(k)
-		return node.Value.(internalProvider), true
 	}
-	return nil, false
+	return nil, nil, false
 }
 
-func (p parameter) ResolveValue(c *Container) (reflect.Value, error) {
-	provider, exists := p.ResolveProvider(c)
+// ResolveValue resolves parameter value. Context parameter without provider resolves as the context of
+// resolving. Interface parameter without provider is bound automatically if auto binding enabled.
+func (p parameter) ResolveValue(ctx context.Context, c *Container) (reflect.Value, error) {
+	return p.resolveValue(ctx, c, c)
+}
+
+// resolveValue resolves parameter value. Provider of parent container builds value with types of the parent.
+// Singletons are owned by container that provides them, cleanups of other values are registered in scope that
+// requested them.
+func (p parameter) resolveValue(ctx context.Context, c *Container, scope *Container) (reflect.Value, error) {
+	provider, owner, exists := p.resolveProvider(c)
+	if !exists && p.isContext() {
+		return reflect.ValueOf(&ctx).Elem(), nil
+	}
+	if dependency, ok := p.lazyOf(); !exists && ok {
+		if _, exists := dependency.ResolveProvider(c); exists {
+			return p.lazyValue(c, dependency), nil
+		}
+	}
+	if !exists && c.autoBinding {
+		provider, exists = p.autoBindingProvider(c)
+		owner = c
+	}
 	if !exists && p.optional {
 		return reflect.New(p.res).Elem(), nil
 	}
 	if !exists {
-		return reflect.Value{}, ErrParameterProviderNotFound{param: p}
+		return reflect.Value{}, ErrParameterProviderNotFound{param: p, suggestions: c.suggestions(p)}
+	}
+	return resolveProviderValue(ctx, owner, scope, provider)
+}
+
+// isContext checks that parameter is an unnamed context.Context.
+func (p parameter) isContext() bool {
+	return p.name == "" && p.res == contextType
+}
+
+// resolveProviderValue resolves provider parameters in container and provides value. Cleanup of singleton is
+// registered in container, cleanups of other values are registered in scope. It stops if context is done.
+func resolveProviderValue(ctx context.Context, c *Container, scope *Container, provider internalProvider) (reflect.Value, error) {
+	// singleton is locked during the whole construction, concurrent resolving waits for the first one
+	if singleton, ok := provider.(*singletonWrapper); ok {
+		singleton.mu.Lock()
+		defer singleton.mu.Unlock()
+		if singleton.value.IsValid() {
+			return singleton.value, nil
+		}
+		scope = c
 	}
 	pl := provider.ParameterList()
-	values, err := pl.Resolve(c)
+	values, err := pl.resolve(ctx, c, scope)
 	if err != nil {
-		return reflect.Value{}, err
+		return reflect.Value{}, withConsumer(err, provider.Key())
+	}
+	if err := ctx.Err(); err != nil {
+		return reflect.Value{}, ErrParameterProvideFailed{k: provider.Key(), err: err}
 	}
 	value, cleanup, err := provider.Provide(values...)
 	if err != nil {
 		return value, ErrParameterProvideFailed{k: provider.Key(), err: err}
 	}
 	if cleanup != nil {
-		c.cleanups = append(c.cleanups, cleanup)
+		scope.addCleanup(provider.Key(), cleanup)
+	}
+	// decorated interfaces are singletons too, but their components are already detected
+	if singleton, ok := provider.(*singletonWrapper); ok && singleton.Key().typ == ptConstructor {
+		c.lifecycle.detect(value)
 	}
 	return value, nil
 }
 
-// isEmbedParameter
+// isEmbedParameter checks that type is a structure that embeds Parameter. Structures created by reflect.StructOf()
+// do not implement methods of embedded fields, so the embedded field is checked too.
 func isEmbedParameter(typ reflect.Type) bool {
-	return typ.Kind() == reflect.Struct && typ.Implements(parameterInterface)
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	if typ.Implements(parameterInterface) {
+		return true
+	}
+	field, ok := typ.FieldByName("Parameter")
+	return ok && field.Anonymous && field.Type == parameterType
 }
 
 // internalParameter
@@ -73,3 +139,9 @@ type internalParameter interface {
 
 // parameterInterface
 var parameterInterface = reflect.TypeOf(new(internalParameter)).Elem()
+
+// parameterType
+var parameterType = reflect.TypeOf(Parameter{})
+
+// contextType
+var contextType = reflect.TypeOf(new(context.Context)).Elem()
@@ -3,11 +3,17 @@ package di
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/defval/inject/v2/di/internal/reflection"
 )
 
 // createParameterBugProvider
 func createParameterBugProvider(key key, parameters ParameterBag) internalProvider {
-	return newProviderConstructor(key.String(), func() ParameterBag { return parameters })
+	return &providerConstructor{
+		name:     key.String(),
+		ctor:     reflection.InspectFunction(func() ParameterBag { return parameters }),
+		ctorType: ctorStd,
+	}
 }
 
 // parameterBagType
@@ -1,15 +1,24 @@
 package di
 
-import "reflect"
+import (
+	"context"
+	"reflect"
+)
 
 // parameterList
 type parameterList []parameter
 
 // ResolveValues loads all parameters presented in parameter list.
-func (pl parameterList) Resolve(c *Container) ([]reflect.Value, error) {
+func (pl parameterList) Resolve(ctx context.Context, c *Container) ([]reflect.Value, error) {
+	return pl.resolve(ctx, c, c)
+}
+
+// resolve loads all parameters presented in parameter list. Cleanups of created non-singleton values are
+// registered in scope.
+func (pl parameterList) resolve(ctx context.Context, c *Container, scope *Container) ([]reflect.Value, error) {
 	var values []reflect.Value
 	for _, p := range pl {
-		value, err := p.ResolveValue(c)
+		value, err := p.resolveValue(ctx, c, scope)
 		if err != nil {
 			return nil, err
 		}
@@ -3,7 +3,7 @@ package di
 import "reflect"
 
 // provider lookup sequence
-var providerLookupSequence = []providerType{ptConstructor, ptInterface, ptGroup, ptEmbedParameter}
+var providerLookupSequence = []providerType{ptConstructor, ptSupplied, ptInterface, ptGroup, ptEmbedParameter, ptResults}
 
 // providerType
 type providerType int
@@ -14,6 +14,8 @@ const (
 	ptInterface
 	ptGroup
 	ptEmbedParameter
+	ptSupplied
+	ptResults
 )
 
 // provider
@@ -23,5 +25,5 @@ type internalProvider interface {
 	// ParameterList returns array of dependencies.
 	ParameterList() parameterList
 	// Provide provides value from provided parameters.
-	Provide(values ...reflect.Value) (reflect.Value, func(), error)
+	Provide(values ...reflect.Value) (reflect.Value, func() error, error)
 }
@@ -2,7 +2,6 @@ package di
 
 import (
 	"errors"
-	"fmt"
 	"reflect"
 
 	"github.com/defval/inject/v2/di/internal/reflection"
@@ -14,25 +13,28 @@ const (
 	ctorUnknown      ctorType = iota // unknown ctor signature
 	ctorStd                          // (deps) (result)
 	ctorError                        // (deps) (result, error)
-	ctorCleanup                      // (deps) (result, cleanup)
-	ctorCleanupError                 // (deps) (result, cleanup, error)
+	ctorCleanup                      // (deps) (result, cleanup), cleanup is func() or func() error
+	ctorCleanupError                 // (deps) (result, cleanup, error), cleanup is func() or func() error
 )
 
 // newProviderConstructor
-func newProviderConstructor(name string, ctor interface{}) *providerConstructor {
+func newProviderConstructor(name string, ctor interface{}) (*providerConstructor, error) {
 	if ctor == nil {
-		panicf("The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `%s`", "nil")
+		return nil, ErrInvalidConstructor{ctor: "nil"}
 	}
 	if !reflection.IsFunc(ctor) {
-		panicf("The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `%s`", reflect.ValueOf(ctor).Type())
+		return nil, ErrInvalidConstructor{ctor: reflect.ValueOf(ctor).Type().String()}
 	}
 	fn := reflection.InspectFunction(ctor)
-	ctorType := determineCtorType(fn)
+	ctorType, err := determineCtorType(fn)
+	if err != nil {
+		return nil, err
+	}
 	return &providerConstructor{
 		name:     name,
 		ctor:     fn,
 		ctorType: ctorType,
-	}
+	}, nil
 }
 
 // providerConstructor
@@ -71,7 +73,7 @@ func (c providerConstructor) ParameterList() parameterList {
 }
 
 // Provide
-func (c *providerConstructor) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
+func (c *providerConstructor) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
 	out := callResult(c.ctor.Call(values))
 	switch c.ctorType {
 	case ctorStd:
@@ -88,22 +90,22 @@ func (c *providerConstructor) Provide(values ...reflect.Value) (reflect.Value, f
 }
 
 // determineCtorType
-func determineCtorType(fn *reflection.Func) ctorType {
+func determineCtorType(fn *reflection.Func) (ctorType, error) {
 	if fn.NumOut() == 1 {
-		return ctorStd
+		return ctorStd, nil
 	}
 	if fn.NumOut() == 2 {
 		if reflection.IsError(fn.Out(1)) {
-			return ctorError
+			return ctorError, nil
 		}
 		if reflection.IsCleanup(fn.Out(1)) {
-			return ctorCleanup
+			return ctorCleanup, nil
 		}
 	}
 	if fn.NumOut() == 3 && reflection.IsCleanup(fn.Out(1)) && reflection.IsError(fn.Out(2)) {
-		return ctorCleanupError
+		return ctorCleanupError, nil
 	}
-	panic(fmt.Sprintf("The constructor must be a function like `func([dep1, dep2, ...]) (<result>, [cleanup, error])`, got `%s`", fn.Name))
+	return ctorUnknown, ErrInvalidConstructor{ctor: fn.Name}
 }
 
 // callResult
@@ -113,13 +115,31 @@ func (r callResult) instance() reflect.Value {
 	return r[0]
 }
 
-func (r callResult) cleanup() func() {
-	if r[1].IsNil() {
+func (r callResult) cleanup() func() error {
+	return r.cleanupAt(1)
+}
+
+func (r callResult) cleanupAt(position int) func() error {
+	if r[position].IsNil() {
 		return nil
 	}
-	return r[1].Interface().(func())
+	// named cleanup types are converted to unnamed
+	value := r[position]
+	if value.Type().NumOut() == 0 {
+		cleanup := value.Convert(cleanupType).Interface().(func())
+		return func() error {
+			cleanup()
+			return nil
+		}
+	}
+	return value.Convert(cleanupErrorType).Interface().(func() error)
 }
 
+var (
+	cleanupType      = reflect.TypeOf(func() {})
+	cleanupErrorType = reflect.TypeOf(func() error { return nil })
+)
+
 func (r callResult) error(position int) error {
 	if r[position].IsNil() {
 		return nil
@@ -0,0 +1,97 @@
+package di
+
+import (
+	"reflect"
+
+	"github.com/defval/inject/v2/di/internal/reflection"
+)
+
+// inspectDecorator checks that decorator is a function that takes the decorated type as the first parameter and
+// returns the same type.
+func inspectDecorator(decorator interface{}) (*reflection.Func, ctorType, error) {
+	if decorator == nil {
+		return nil, ctorUnknown, ErrInvalidDecorator{decorator: "nil"}
+	}
+	if !reflection.IsFunc(decorator) {
+		return nil, ctorUnknown, ErrInvalidDecorator{decorator: reflect.TypeOf(decorator).String()}
+	}
+	fn := reflection.InspectFunction(decorator)
+	if fn.NumIn() == 0 || fn.NumOut() == 0 || fn.In(0) != fn.Out(0) {
+		return nil, ctorUnknown, ErrInvalidDecorator{decorator: fn.Type.String()}
+	}
+	ctorType, err := determineCtorType(fn)
+	if err != nil {
+		return nil, ctorUnknown, ErrInvalidDecorator{decorator: fn.Type.String()}
+	}
+	return fn, ctorType, nil
+}
+
+// newProviderDecorator creates decorator of provider.
+func newProviderDecorator(provider internalProvider, fn *reflection.Func, ctorType ctorType) *providerDecorator {
+	return &providerDecorator{
+		provider: provider,
+		decorator: &providerConstructor{
+			name:     provider.Key().name,
+			ctor:     fn,
+			ctorType: ctorType,
+		},
+	}
+}
+
+// providerDecorator provides decorated result of source provider.
+type providerDecorator struct {
+	provider  internalProvider
+	decorator *providerConstructor
+}
+
+// Key
+func (d *providerDecorator) Key() key {
+	return d.provider.Key()
+}
+
+// ParameterList returns parameters of source provider and decorator parameters except decorated one.
+func (d *providerDecorator) ParameterList() parameterList {
+	var plist parameterList
+	plist = append(plist, d.provider.ParameterList()...)
+	plist = append(plist, d.decorator.ParameterList()[1:]...)
+	return plist
+}
+
+// Provide
+func (d *providerDecorator) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	n := len(d.provider.ParameterList())
+	value, cleanup, err := d.provider.Provide(values[:n]...)
+	if err != nil {
+		return value, cleanup, err
+	}
+	decorated, decoratorCleanup, err := d.decorator.Provide(append([]reflect.Value{value}, values[n:]...)...)
+	if err != nil {
+		if cleanup != nil {
+			_ = cleanup()
+		}
+		return decorated, nil, err
+	}
+	return decorated, joinCleanups(decoratorCleanup, cleanup), nil
+}
+
+// joinCleanups joins cleanups into one that runs them in order and returns the first error.
+func joinCleanups(cleanups ...func() error) func() error {
+	var fns []func() error
+	for _, cleanup := range cleanups {
+		if cleanup != nil {
+			fns = append(fns, cleanup)
+		}
+	}
+	if len(fns) == 0 {
+		return nil
+	}
+	return func() error {
+		var result error
+		for _, fn := range fns {
+			if err := fn(); err != nil && result == nil {
+				result = err
+			}
+		}
+		return result
+	}
+}
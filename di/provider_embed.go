@@ -14,7 +14,7 @@ func newProviderEmbed(p parameter) *providerEmbed {
 		embedType = p.res
 	}
 
-	return &providerEmbed{
+	provider := &providerEmbed{
 		key: key{
 			name: p.name,
 			res:  p.res,
@@ -23,12 +23,20 @@ func newProviderEmbed(p parameter) *providerEmbed {
 		embedType:  embedType,
 		embedValue: reflect.New(embedType).Elem(),
 	}
+	// collect incorrect tags, compile reports them
+	for i := 0; i < embedType.NumField(); i++ {
+		if _, _, _, err := provider.inspectFieldTag(i); err != nil {
+			provider.errs = append(provider.errs, err)
+		}
+	}
+	return provider
 }
 
 type providerEmbed struct {
 	key        key
 	embedType  reflect.Type
 	embedValue reflect.Value
+	errs       []error
 }
 
 func (p *providerEmbed) Key() key {
@@ -38,7 +46,7 @@ func (p *providerEmbed) Key() key {
 func (p *providerEmbed) ParameterList() parameterList {
 	var plist parameterList
 	for i := 0; i < p.embedType.NumField(); i++ {
-		name, optional, isDependency := p.inspectFieldTag(i)
+		name, optional, isDependency, _ := p.inspectFieldTag(i)
 		if !isDependency {
 			continue
 		}
@@ -53,44 +61,50 @@ func (p *providerEmbed) ParameterList() parameterList {
 	return plist
 }
 
-func (p *providerEmbed) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
+func (p *providerEmbed) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	// new value for each call, provider can be used concurrently
+	embedValue := reflect.New(p.embedType).Elem()
 	for i, offset := 0, 0; i < p.embedType.NumField(); i++ {
-		_, _, isDependency := p.inspectFieldTag(i)
+		_, _, isDependency, _ := p.inspectFieldTag(i)
 		if !isDependency {
 			offset++
 			continue
 		}
 
-		p.embedValue.Field(i).Set(values[i-offset])
+		embedValue.Field(i).Set(values[i-offset])
 	}
 
-	return p.embedValue, nil, nil
+	return embedValue, nil, nil
 }
 
-func (p *providerEmbed) inspectFieldTag(num int) (name string, optional bool, isDependency bool) {
+// inspectFieldTag parses `di` tag of the field. Fields with incorrect tag are not dependencies.
+func (p *providerEmbed) inspectFieldTag(num int) (name string, optional bool, isDependency bool, err error) {
 	fieldType := p.embedType.Field(num)
 	fieldValue := p.embedValue.Field(num)
 	tag, tagExists := fieldType.Tag.Lookup("di")
 	if !tagExists || !fieldValue.CanSet() {
-		return "", false, false
+		return "", false, false, nil
+	}
+	name, optional, ok := p.parseTag(tag)
+	if !ok {
+		return "", false, false, ErrInvalidTag{consumer: p.key, field: fieldType.Name, tag: tag}
 	}
-	name, optional = p.parseTag(tag)
-	return name, optional, true
+	return name, optional, true, nil
 }
 
-func (p *providerEmbed) parseTag(tag string) (name string, optional bool) {
+func (p *providerEmbed) parseTag(tag string) (name string, optional bool, ok bool) {
 	options := strings.Split(tag, ",")
 	if len(options) == 0 {
-		return "", false
+		return "", false, true
 	}
 	if len(options) == 1 && options[0] == "optional" {
-		return "", true
+		return "", true, true
 	}
 	if len(options) == 1 {
-		return options[0], false
+		return options[0], false, true
 	}
 	if len(options) == 2 && options[1] == "optional" {
-		return options[0], true
+		return options[0], true, true
 	}
-	panic("incorrect di tag")
+	return "", false, false
 }
@@ -0,0 +1,139 @@
+package di
+
+import (
+	"reflect"
+)
+
+// Arguments marks structure as runtime arguments of factory constructor. Exported fields of the structure are
+// arguments of the factory function.
+//
+//   type WorkerArguments struct {
+//     di.Arguments
+//     JobID string
+//   }
+//
+//   func NewWorker(args WorkerArguments, db *sql.DB) (*Worker, error)
+//
+//   // container provides func(jobID string) (*Worker, error)
+type Arguments struct {
+	internalArguments
+}
+
+// internalArguments
+type internalArguments interface {
+	isDependencyInjectionArguments()
+}
+
+// argumentsInterface
+var argumentsInterface = reflect.TypeOf(new(internalArguments)).Elem()
+
+// errorType
+var errorType = reflect.TypeOf(new(error)).Elem()
+
+// newProviderFactory creates provider of factory function. Factory takes exported fields of arguments structure
+// and returns constructor result, cleanup if constructor returns it, and error.
+func newProviderFactory(name string, ctor interface{}) (*providerFactory, error) {
+	constructor, err := newProviderConstructor(name, ctor)
+	if err != nil {
+		return nil, err
+	}
+	argsIndex := -1
+	for i := 0; i < constructor.ctor.NumIn(); i++ {
+		typ := constructor.ctor.In(i)
+		if typ.Kind() == reflect.Struct && typ.Implements(argumentsInterface) {
+			argsIndex = i
+			break
+		}
+	}
+	if argsIndex == -1 {
+		return nil, ErrInvalidFactory{ctor: constructor.ctor.Type.String()}
+	}
+	argsType := constructor.ctor.In(argsIndex)
+	var fields []int
+	var in []reflect.Type
+	for i := 0; i < argsType.NumField(); i++ {
+		field := argsType.Field(i)
+		if field.Anonymous && field.Type == reflect.TypeOf(Arguments{}) || field.PkgPath != "" {
+			continue
+		}
+		fields = append(fields, i)
+		in = append(in, field.Type)
+	}
+	out := []reflect.Type{constructor.Key().res, errorType}
+	if constructor.ctorType == ctorCleanup || constructor.ctorType == ctorCleanupError {
+		out = []reflect.Type{constructor.Key().res, constructor.ctor.Out(1), errorType}
+	}
+	return &providerFactory{
+		constructor: constructor,
+		argsIndex:   argsIndex,
+		fields:      fields,
+		typ:         reflect.FuncOf(in, out, false),
+	}, nil
+}
+
+// providerFactory
+type providerFactory struct {
+	constructor *providerConstructor
+	argsIndex   int   // index of arguments structure in constructor parameters
+	fields      []int // indexes of argument fields
+	typ         reflect.Type
+}
+
+// Key
+func (f *providerFactory) Key() key {
+	return key{
+		name: f.constructor.name,
+		res:  f.typ,
+		typ:  ptConstructor,
+	}
+}
+
+// ParameterList returns constructor parameters except arguments structure.
+func (f *providerFactory) ParameterList() parameterList {
+	var plist parameterList
+	for i, p := range f.constructor.ParameterList() {
+		if i == f.argsIndex {
+			continue
+		}
+		plist = append(plist, p)
+	}
+	return plist
+}
+
+// Provide returns factory function. Cleanup of created instance is returned to the caller, container does not own
+// it.
+func (f *providerFactory) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	factory := reflect.MakeFunc(f.typ, func(args []reflect.Value) []reflect.Value {
+		argsValue := reflect.New(f.constructor.ctor.In(f.argsIndex)).Elem()
+		for i, field := range f.fields {
+			argsValue.Field(field).Set(args[i])
+		}
+		var in []reflect.Value
+		in = append(in, values[:f.argsIndex]...)
+		in = append(in, argsValue)
+		in = append(in, values[f.argsIndex:]...)
+		out := make([]reflect.Value, f.typ.NumOut())
+		for i := range out {
+			out[i] = reflect.New(f.typ.Out(i)).Elem()
+		}
+		result := callResult(f.constructor.ctor.Call(in))
+		var err error
+		switch f.constructor.ctorType {
+		case ctorError:
+			err = result.error(1)
+		case ctorCleanupError:
+			err = result.error(2)
+		}
+		// cleanup returned together with error is not run, as with constructor
+		if err != nil {
+			out[len(out)-1].Set(reflect.ValueOf(ErrParameterProvideFailed{k: f.constructor.Key(), err: err}))
+			return out
+		}
+		out[0] = result.instance()
+		if len(out) == 3 {
+			out[1] = result[1]
+		}
+		return out
+	})
+	return factory, nil, nil
+}
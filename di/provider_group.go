@@ -4,33 +4,102 @@ import (
 	"reflect"
 )
 
-// newProviderGroup creates new group from provided resultKey.
-func newProviderGroup(k key) *providerGroup {
+// newProviderGroup creates new group of interface with name.
+func newProviderGroup(name string, k key) *providerGroup {
 	ifaceKey := key{
-		res: reflect.SliceOf(k.res),
-		typ: ptGroup,
+		name: name,
+		res:  reflect.SliceOf(k.res),
+		typ:  ptGroup,
 	}
 
 	return &providerGroup{
 		result: ifaceKey,
-		pl:     parameterList{},
+	}
+}
+
+// newProviderMap creates new group of interface with name that is provided as map keyed by provider names.
+func newProviderMap(name string, k key) *providerGroup {
+	return &providerGroup{
+		result: key{
+			name: name,
+			res:  reflect.MapOf(reflect.TypeOf(""), k.res),
+			typ:  ptGroup,
+		},
 	}
 }
 
 // providerGroup
 type providerGroup struct {
-	result key
-	pl     parameterList
+	result  key
+	members []groupMember
 }
 
-// Add
-func (i *providerGroup) Add(k key) {
-	i.pl = append(i.pl, parameter{
-		name:     k.name,
-		res:      k.res,
-		optional: false,
-		embed:    false,
-	})
+// groupMember is a provider of group. Member resolves through the parameter, it is the provider key itself
+// or the decorated interface of the provider.
+type groupMember struct {
+	k        key
+	param    parameter
+	priority int
+}
+
+// Add adds provider into group. Provider of scope overrides provider of parent with the same key. Map group
+// contains named providers only. Members are ordered by priority, lower first. Members with the same priority keep
+// the order of adding.
+func (i *providerGroup) Add(k key, priority int) {
+	if i.isMap() && k.name == "" {
+		return
+	}
+	k = key{name: k.name, res: k.res}
+	for _, m := range i.members {
+		if m.k == k {
+			return
+		}
+	}
+	pos := len(i.members)
+	for pos > 0 && i.members[pos-1].priority > priority {
+		pos--
+	}
+	i.members = append(i.members, groupMember{})
+	copy(i.members[pos+1:], i.members[pos:])
+	i.members[pos] = groupMember{
+		k: k,
+		param: parameter{
+			name:     k.name,
+			res:      k.res,
+			optional: false,
+			embed:    false,
+		},
+		priority: priority,
+	}
+}
+
+// redirect makes member of provider key resolve through interface.
+func (i *providerGroup) redirect(k key, iface key) {
+	for j := range i.members {
+		if i.members[j].k == (key{name: k.name, res: k.res}) {
+			i.members[j].param = parameter{name: iface.name, res: iface.res}
+		}
+	}
+}
+
+// ambiguous checks that map group has several providers with the same name.
+func (i *providerGroup) ambiguous() bool {
+	if !i.isMap() {
+		return false
+	}
+	names := map[string]bool{}
+	for _, m := range i.members {
+		if names[m.k.name] {
+			return true
+		}
+		names[m.k.name] = true
+	}
+	return false
+}
+
+// isMap
+func (i *providerGroup) isMap() bool {
+	return i.result.res.Kind() == reflect.Map
 }
 
 // resultKey
@@ -40,11 +109,22 @@ func (i providerGroup) Key() key {
 
 // parameters
 func (i providerGroup) ParameterList() parameterList {
-	return i.pl
+	plist := parameterList{}
+	for _, m := range i.members {
+		plist = append(plist, m.param)
+	}
+	return plist
 }
 
 // Provide
-func (i providerGroup) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
+func (i providerGroup) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	if i.isMap() {
+		group := reflect.MakeMapWithSize(i.result.res, len(values))
+		for j, value := range values {
+			group.SetMapIndex(reflect.ValueOf(i.members[j].k.name), value)
+		}
+		return group, nil, nil
+	}
 	group := reflect.New(i.result.res).Elem()
 	return reflect.Append(group, values...), nil, nil
 }
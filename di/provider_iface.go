@@ -6,25 +6,39 @@ import (
 	"github.com/defval/inject/v2/di/internal/reflection"
 )
 
-// newProviderInterface
-func newProviderInterface(provider internalProvider, as interface{}) *providerInterface {
-	iface := reflection.InspectInterfacePtr(as)
+// newProviderInterface creates interface of provider. As is a pointer to interface or Interface.
+func newProviderInterface(provider internalProvider, as interface{}) (*providerInterface, error) {
+	binding, ok := as.(Interface)
+	if !ok {
+		binding = Interface{Type: as}
+	}
+	if !reflection.IsInterfacePtr(binding.Type) {
+		return nil, ErrInvalidInterface{typ: reflect.TypeOf(binding.Type)}
+	}
+	iface := reflection.InspectInterfacePtr(binding.Type)
 	if !provider.Key().res.Implements(iface.Type) {
-		panicf("%s not implement %s", provider.Key(), iface.Type)
+		return nil, ErrNotImplementInterface{k: provider.Key(), iface: iface.Type}
+	}
+	name := provider.Key().name
+	if binding.Name != "" {
+		name = binding.Name
 	}
 	return &providerInterface{
 		res: key{
-			name: provider.Key().name,
+			name: name,
 			res:  iface.Type,
 			typ:  ptInterface,
 		},
+		group:    binding.Group,
 		provider: provider,
-	}
+	}, nil
 }
 
 // providerInterface
 type providerInterface struct {
 	res      key
+	group    string // name of interface group
+	primary  bool   // primary implementation of interface
 	provider internalProvider
 }
 
@@ -43,6 +57,6 @@ func (i *providerInterface) ParameterList() parameterList {
 	return plist
 }
 
-func (i *providerInterface) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
+func (i *providerInterface) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
 	return values[0], nil, nil
 }
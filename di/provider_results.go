@@ -0,0 +1,227 @@
+package di
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/defval/inject/v2/di/internal/reflection"
+)
+
+// Result marks structure as a result object of constructor. Every field with `di` tag is provided as a separate
+// type, the tag value sets its name. The `as` tag option binds the field to interfaces of As() provide option,
+// other fields are not bound then. Cleanup returned together with non-nil error is not run, as with constructor.
+//
+//   type Connections struct {
+//     di.Result
+//     Primary *sql.DB `di:"primary,as"`
+//     Replica *sql.DB `di:"replica"`
+//   }
+//
+//   func NewConnections(dsn DSN) (Connections, error)
+type Result struct {
+	internalResult
+}
+
+// internalResult
+type internalResult interface {
+	isDependencyInjectionResult()
+}
+
+// resultInterface
+var resultInterface = reflect.TypeOf(new(internalResult)).Elem()
+
+// isResultObject checks that type is a structure that embeds Result.
+func isResultObject(typ reflect.Type) bool {
+	return typ.Kind() == reflect.Struct && typ.Implements(resultInterface)
+}
+
+// isResultsConstructor checks that constructor returns several values or result object.
+func isResultsConstructor(ctor interface{}) bool {
+	if !reflection.IsFunc(ctor) {
+		return false
+	}
+	fn := reflection.InspectFunction(ctor)
+	n, _, _ := resultsLayout(fn)
+	return n > 1 || n == 1 && isResultObject(fn.Out(0))
+}
+
+// resultsLayout returns number of result values and positions of cleanup and error results, -1 if they are absent.
+func resultsLayout(fn *reflection.Func) (n int, cleanupIndex int, errorIndex int) {
+	n, cleanupIndex, errorIndex = fn.NumOut(), -1, -1
+	if n > 0 && reflection.IsError(fn.Out(n-1)) {
+		n--
+		errorIndex = n
+	}
+	if n > 0 && reflection.IsCleanup(fn.Out(n-1)) {
+		n--
+		cleanupIndex = n
+	}
+	return n, cleanupIndex, errorIndex
+}
+
+// resultField is a one of constructor results.
+type resultField struct {
+	name  string
+	res   reflect.Type
+	index int  // index of result or field of result object
+	as    bool // field is bound to interfaces explicitly
+}
+
+// newProviderResults creates provider of constructor with several results. Name is used for results without
+// tag name.
+func newProviderResults(name string, ctor interface{}) (*providerResults, error) {
+	fn := reflection.InspectFunction(ctor)
+	n, cleanupIndex, errorIndex := resultsLayout(fn)
+	provider := &providerResults{
+		name:         name,
+		ctor:         fn,
+		cleanupIndex: cleanupIndex,
+		errorIndex:   errorIndex,
+	}
+	if n == 1 {
+		provider.object = true
+		objectType := fn.Out(0)
+		objectValue := reflect.New(objectType).Elem()
+		for i := 0; i < objectType.NumField(); i++ {
+			field := objectType.Field(i)
+			tag, ok := field.Tag.Lookup("di")
+			if !ok || !objectValue.Field(i).CanSet() {
+				continue
+			}
+			fieldName, as, ok := parseResultTag(tag)
+			if !ok {
+				return nil, ErrInvalidTag{consumer: provider.Key(), field: field.Name, tag: tag}
+			}
+			if fieldName == "" {
+				fieldName = name
+			}
+			provider.fields = append(provider.fields, resultField{name: fieldName, res: field.Type, index: i, as: as})
+		}
+		if len(provider.fields) == 0 {
+			return nil, ErrInvalidConstructor{ctor: fn.Name}
+		}
+		return provider, nil
+	}
+	for i := 0; i < n; i++ {
+		if reflection.IsError(fn.Out(i)) || reflection.IsCleanup(fn.Out(i)) {
+			return nil, ErrInvalidConstructor{ctor: fn.Name}
+		}
+		provider.fields = append(provider.fields, resultField{name: name, res: fn.Out(i), index: i})
+	}
+	return provider, nil
+}
+
+// parseResultTag parses `di` tag of result object field: name and `as` option.
+func parseResultTag(tag string) (name string, as bool, ok bool) {
+	options := strings.Split(tag, ",")
+	if len(options) == 1 && options[0] == "as" {
+		return "", true, true
+	}
+	if len(options) == 1 {
+		return options[0], false, true
+	}
+	if len(options) == 2 && options[1] == "as" {
+		return options[0], true, true
+	}
+	return "", false, false
+}
+
+// providerResults calls constructor once for all its results.
+type providerResults struct {
+	name         string
+	ctor         *reflection.Func
+	object       bool
+	fields       []resultField
+	cleanupIndex int
+	errorIndex   int
+}
+
+// Key
+func (p *providerResults) Key() key {
+	return key{
+		name: p.name,
+		res:  p.ctor.Type,
+		typ:  ptResults,
+	}
+}
+
+// ParameterList
+func (p *providerResults) ParameterList() parameterList {
+	var plist parameterList
+	for i := 0; i < p.ctor.NumIn(); i++ {
+		ptype := p.ctor.In(i)
+		var name string
+		if ptype == parameterBagType {
+			name = p.Key().String()
+		}
+		plist = append(plist, parameter{
+			name:  name,
+			res:   ptype,
+			embed: isEmbedParameter(ptype),
+		})
+	}
+	return plist
+}
+
+// Provide provides values of results as []reflect.Value.
+func (p *providerResults) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	out := callResult(p.ctor.Call(values))
+	var cleanup func() error
+	if p.cleanupIndex != -1 {
+		cleanup = out.cleanupAt(p.cleanupIndex)
+	}
+	if p.errorIndex != -1 {
+		if err := out.error(p.errorIndex); err != nil {
+			return reflect.Value{}, cleanup, err
+		}
+	}
+	var results []reflect.Value
+	for _, field := range p.fields {
+		if p.object {
+			results = append(results, out[0].Field(field.index))
+		} else {
+			results = append(results, out[field.index])
+		}
+	}
+	return reflect.ValueOf(results), cleanup, nil
+}
+
+// newProviderResult
+func newProviderResult(results *providerResults, index int) *providerResult {
+	return &providerResult{
+		results: results.Key(),
+		field:   results.fields[index],
+		index:   index,
+	}
+}
+
+// providerResult provides one of constructor results.
+type providerResult struct {
+	results key
+	field   resultField
+	index   int
+}
+
+// Key
+func (p *providerResult) Key() key {
+	return key{
+		name: p.field.name,
+		res:  p.field.res,
+		typ:  ptConstructor,
+	}
+}
+
+// ParameterList
+func (p *providerResult) ParameterList() parameterList {
+	return parameterList{
+		parameter{
+			name: p.results.name,
+			res:  p.results.res,
+		},
+	}
+}
+
+// Provide
+func (p *providerResult) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	return values[0].Interface().([]reflect.Value)[p.index], nil, nil
+}
@@ -1,7 +1,7 @@
 package di
 
 import (
-	"fmt"
+	"errors"
 	"reflect"
 )
 
@@ -24,6 +24,6 @@ func (m *providerStub) ParameterList() parameterList {
 	return parameterList{}
 }
 
-func (m *providerStub) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
-	return reflect.Value{}, nil, fmt.Errorf(m.msg)
+func (m *providerStub) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	return reflect.Value{}, nil, errors.New(m.msg)
 }
@@ -0,0 +1,41 @@
+package di
+
+import (
+	"reflect"
+)
+
+// newProviderSupplied
+func newProviderSupplied(name string, value interface{}) (*providerSupplied, error) {
+	if value == nil {
+		return nil, ErrInvalidSupply{}
+	}
+	return &providerSupplied{
+		name:  name,
+		value: reflect.ValueOf(value),
+	}, nil
+}
+
+// providerSupplied provides ready-made value.
+type providerSupplied struct {
+	name  string
+	value reflect.Value
+}
+
+// Key
+func (s *providerSupplied) Key() key {
+	return key{
+		name: s.name,
+		res:  s.value.Type(),
+		typ:  ptSupplied,
+	}
+}
+
+// ParameterList
+func (s *providerSupplied) ParameterList() parameterList {
+	return parameterList{}
+}
+
+// Provide
+func (s *providerSupplied) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
+	return s.value, nil, nil
+}
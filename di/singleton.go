@@ -2,6 +2,7 @@ package di
 
 import (
 	"reflect"
+	"sync"
 )
 
 // asSingleton creates a singleton wrapper.
@@ -12,16 +13,19 @@ func asSingleton(provider internalProvider) *singletonWrapper {
 // singletonWrapper is a embedParamProvider wrapper. Stores provided value for prevent reinitialization.
 type singletonWrapper struct {
 	internalProvider               // source provider
+	mu               sync.Mutex    // guards value construction
 	value            reflect.Value // value cache
 }
 
 // Provide
-func (s *singletonWrapper) Provide(values ...reflect.Value) (reflect.Value, func(), error) {
+func (s *singletonWrapper) Provide(values ...reflect.Value) (reflect.Value, func() error, error) {
 	if s.value.IsValid() {
 		return s.value, nil, nil
 	}
 	value, cleanup, err := s.internalProvider.Provide(values...)
+	if err != nil {
+		return value, cleanup, err
+	}
 	s.value = value
-
 	return value, cleanup, err
 }
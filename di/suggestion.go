@@ -0,0 +1,54 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// suggestions returns keys of provided types that look like the parameter. It covers the most common wiring
+// mistakes: pointer instead of non-pointer type and vice versa, the same type with another name, the interface
+// implementation that was not provided with As() and the group that exists only as slice.
+func (c *Container) suggestions(p parameter) []key {
+	var result []key
+	for _, node := range c.graph.Nodes() {
+		k := node.Key.(key)
+		if k.name == p.name && k.res == p.res {
+			continue
+		}
+		if k.res == p.res || isPointerPair(k.res, p.res) || isNotBoundImplementation(k, p.res) || isGroupOf(k, p.res) {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+// isPointerPair checks that one type is a pointer to another.
+func isPointerPair(a, b reflect.Type) bool {
+	return a == reflect.PtrTo(b) || reflect.PtrTo(a) == b
+}
+
+// isNotBoundImplementation checks that constructor result implements the interface or the interface of the group.
+func isNotBoundImplementation(k key, iface reflect.Type) bool {
+	if iface.Kind() == reflect.Slice {
+		iface = iface.Elem()
+	}
+	return (k.typ == ptConstructor || k.typ == ptSupplied) && iface.Kind() == reflect.Interface && k.res.Implements(iface)
+}
+
+// isGroupOf checks that the type exists only as a slice.
+func isGroupOf(k key, typ reflect.Type) bool {
+	return k.res.Kind() == reflect.Slice && k.res.Elem() == typ
+}
+
+// suggestionString
+func suggestionString(suggestions []key) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	var names []string
+	for _, k := range suggestions {
+		names = append(names, k.String())
+	}
+	return fmt.Sprintf(" (did you mean %s?)", strings.Join(names, " or "))
+}
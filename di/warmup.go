@@ -0,0 +1,72 @@
+package di
+
+import (
+	"context"
+	"sync"
+)
+
+// Warmup builds every singleton of the container. The dependency graph is split into layers, the layer consists
+// of singletons that depend only on previous layers. Constructors of the layer run concurrently, but no more than
+// parallelism at the same time. Zero or negative parallelism means no limit. Warmup stops on the first error:
+// constructors that are not started yet are skipped. Context is passed into constructors that declare it.
+func (c *Container) Warmup(ctx context.Context, parallelism int) error {
+	if !c.compiled {
+		return ErrNotCompiled{}
+	}
+	nodes := len(c.graph.Nodes())
+	if parallelism <= 0 || parallelism > nodes {
+		parallelism = nodes
+	}
+	layers, err := c.graph.Layers(nodes)
+	if err != nil {
+		return err
+	}
+	// stop is cancelled on the first error, constructors get the original context because they may keep it
+	stop, cancel := context.WithCancel(ctx)
+	defer cancel()
+	semaphore := make(chan struct{}, parallelism)
+	for _, layer := range layers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.warmupLayer(ctx, stop, cancel, layer, semaphore); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// warmupLayer concurrently builds singletons of the layer and returns the first error. Semaphore limits the
+// number of running constructors. The first error cancels stop context, constructors are not started after it.
+func (c *Container) warmupLayer(
+	ctx, stop context.Context, cancel context.CancelFunc, layer []interface{}, semaphore chan struct{},
+) error {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for _, k := range layer {
+		singleton, ok := c.graph.Get(k).Value.(*singletonWrapper)
+		if !ok {
+			continue
+		}
+		semaphore <- struct{}{}
+		if stop.Err() != nil {
+			<-semaphore
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if _, err := resolveProviderValue(ctx, c, c, singleton); err != nil {
+				once.Do(func() { firstErr = err })
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr == nil {
+		return ctx.Err()
+	}
+	return firstErr
+}
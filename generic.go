@@ -0,0 +1,86 @@
+package inject
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/defval/inject/v2/di"
+)
+
+// ExtractT extracts instance of type T from container. It is a type-safe version of Container.Extract().
+//
+//   server, err := inject.ExtractT[*http.Server](container)
+func ExtractT[T any](c *Container, options ...ExtractOption) (T, error) {
+	var result T
+	err := c.Extract(&result, options...)
+	return result, err
+}
+
+// MustExtract extracts instance of type T from container like ExtractT() does. It panics if the instance could not
+// be extracted.
+//
+//   server := inject.MustExtract[*http.Server](container)
+func MustExtract[T any](c *Container, options ...ExtractOption) T {
+	result, err := ExtractT[T](c, options...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return result
+}
+
+// ProvideAs returns container option that provides constructor of type T and provides interface I of the instance
+// through conversion function. Provide options are applied to the constructor, interface I gets the same name.
+// Interface I is a separate type of container, it is not a member of interface groups, use inject.As() for them.
+// The constructor is not typed, container reports an error on build if nobody provides T.
+//
+//   inject.ProvideAs(NewServeMux, func(mux *http.ServeMux) http.Handler { return mux })
+func ProvideAs[T any, I any](ctor interface{}, as func(T) I, options ...ProvideOption) Option {
+	var params = di.ProvideParams{
+		Parameters: map[string]interface{}{},
+	}
+	for _, opt := range options {
+		opt.apply(&params)
+	}
+	return Bundle(
+		Provide(ctor, options...),
+		Provide(conversion(params.Name, as), WithName(params.Name)),
+	)
+}
+
+// InvokeT invokes function with dependency of type A. It is a type-safe version of Container.Invoke().
+//
+//   err := inject.InvokeT(container, func(server *http.Server) error {
+//     return server.ListenAndServe()
+//   })
+func InvokeT[A any](c *Container, fn func(A) error) error {
+	return c.Invoke(fn)
+}
+
+// InvokeT2 invokes function with dependencies of types A and B like InvokeT() does.
+func InvokeT2[A, B any](c *Container, fn func(A, B) error) error {
+	return c.Invoke(fn)
+}
+
+// InvokeT3 invokes function with dependencies of types A, B and C like InvokeT() does.
+func InvokeT3[A, B, C any](c *Container, fn func(A, B, C) error) error {
+	return c.Invoke(fn)
+}
+
+// conversion creates constructor of I that takes T with name and converts it with function as.
+func conversion[T any, I any](name string, as func(T) I) interface{} {
+	if name == "" {
+		return func(t T) I { return as(t) }
+	}
+	// parameter structure with `di:"name"` tag takes named T
+	params := reflect.StructOf([]reflect.StructField{
+		{Name: "Parameter", Type: reflect.TypeOf(di.Parameter{}), Anonymous: true},
+		{Name: "Value", Type: reflect.TypeOf(new(T)).Elem(), Tag: reflect.StructTag(fmt.Sprintf(`di:"%s"`, name))},
+	})
+	fn := reflect.FuncOf([]reflect.Type{params}, []reflect.Type{reflect.TypeOf(new(I)).Elem()}, false)
+	return reflect.MakeFunc(fn, func(args []reflect.Value) []reflect.Value {
+		var t T
+		reflect.ValueOf(&t).Elem().Set(args[0].Field(1))
+		i := as(t)
+		return []reflect.Value{reflect.ValueOf(&i).Elem()}
+	}).Interface()
+}
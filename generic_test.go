@@ -0,0 +1,75 @@
+package inject_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defval/inject/v2"
+	"github.com/defval/inject/v2/di"
+)
+
+func TestExtractT(t *testing.T) {
+	c := inject.New(
+		inject.Provide(ProvideAddr("0.0.0.0", "8080"), inject.WithName("addr")),
+		inject.Provide(NewMux),
+	)
+	addr, err := inject.ExtractT[Addr](c, inject.Name("addr"))
+	require.NoError(t, err)
+	require.Equal(t, Addr("0.0.0.0:8080"), addr)
+	mux := inject.MustExtract[*http.ServeMux](c)
+	require.NotNil(t, mux)
+	_, err = inject.ExtractT[*http.Server](c)
+	require.EqualError(t, err, "*http.Server: not exists in container")
+	require.Panics(t, func() { inject.MustExtract[*http.Server](c) })
+}
+
+func TestProvideAs(t *testing.T) {
+	t.Run("type provided as interface", func(t *testing.T) {
+		c := inject.New(
+			inject.ProvideAs(NewMux, func(mux *http.ServeMux) http.Handler { return mux }),
+		)
+		handler := inject.MustExtract[http.Handler](c)
+		require.Equal(t, inject.MustExtract[*http.ServeMux](c), handler)
+	})
+
+	t.Run("named type provided as named interface", func(t *testing.T) {
+		c := inject.New(
+			inject.ProvideAs(NewMux, func(mux *http.ServeMux) http.Handler { return mux }, inject.WithName("public")),
+		)
+		handler := inject.MustExtract[http.Handler](c, inject.Name("public"))
+		require.Equal(t, inject.MustExtract[*http.ServeMux](c, inject.Name("public")), handler)
+	})
+
+	t.Run("constructor result is not type", func(t *testing.T) {
+		_, err := inject.NewContainer(
+			inject.Provide(ProvideAddr("0.0.0.0", "8080")),
+			inject.ProvideAs(NewHTTPServer, func(mux *http.ServeMux) http.Handler { return mux }),
+		)
+		require.EqualError(t, err, "http.Handler: dependency *http.ServeMux not exists in container")
+		var notFound di.ErrDependencyNotFound
+		require.True(t, errors.As(err, &notFound))
+	})
+}
+
+func TestInvokeT(t *testing.T) {
+	c := inject.New(
+		inject.Provide(ProvideAddr("0.0.0.0", "8080")),
+		inject.ProvideAs(NewMux, func(mux *http.ServeMux) http.Handler { return mux }),
+		inject.Provide(NewHTTPServer),
+	)
+	require.NoError(t, inject.InvokeT(c, func(addr Addr) error {
+		require.Equal(t, Addr("0.0.0.0:8080"), addr)
+		return nil
+	}))
+	require.NoError(t, inject.InvokeT2(c, func(addr Addr, mux *http.ServeMux) error {
+		require.NotNil(t, mux)
+		return nil
+	}))
+	require.NoError(t, inject.InvokeT3(c, func(addr Addr, mux *http.ServeMux, server *http.Server) error {
+		require.Equal(t, string(addr), server.Addr)
+		return nil
+	}))
+}
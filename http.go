@@ -0,0 +1,98 @@
+package inject
+
+import (
+	"context"
+	"net/http"
+)
+
+// Middleware returns net/http middleware that creates a scope of the container for each request. The scope
+// provides *http.Request, context.Context of the request and http.ResponseWriter, options add per-request types.
+// The scope is available through ContainerFromContext() and is cleaned up when the request finishes. Options are
+// checked once: Middleware() returns an error if the scope could not be compiled. If the scope of the request could
+// not be created, e.g. inject.Invoke() option fails, the error is passed into the handler of ErrorHandler() option.
+//
+//   middleware, err := container.Middleware(
+//     inject.Provide(NewTransaction),
+//   )
+//   if err != nil {
+//     // wiring failed
+//   }
+//   handler := middleware(mux)
+//
+//   func (c *AccountController) Get(w http.ResponseWriter, r *http.Request) {
+//     scope, _ := inject.ContainerFromContext(r.Context())
+//     var tx *sql.Tx
+//     if err := scope.Extract(&tx); err != nil {
+//       // handle error
+//     }
+//   }
+func (c *Container) Middleware(options ...Option) (func(http.Handler) http.Handler, error) {
+	// request values are not created, invokes are not called
+	probe := c.child()
+	for _, opt := range append(requestOptions(nil, nil, nil), options...) {
+		opt.apply(probe)
+	}
+	if err := probe.compile(); err != nil {
+		return nil, err
+	}
+	errorHandler := probe.errorHandler
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var scope *Container
+			ctx := context.WithValue(r.Context(), containerKey{}, &scope)
+			r = r.WithContext(ctx)
+			scope, err := c.Scope(append(requestOptions(ctx, w, r), options...)...)
+			if err != nil {
+				errorHandler(w, r, err)
+				return
+			}
+			defer func() {
+				ctx, cancel := context.WithTimeout(context.Background(), c.shutdownTimeout)
+				defer cancel()
+				_ = scope.Cleanup(ctx)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// ErrorHandler sets a handler of request scope failures of Container.Middleware(). Scopes inherit the handler of
+// the container. Default handler responds with 500 Internal Server Error.
+//
+//   middleware, err := container.Middleware(
+//     inject.Invoke(Authorize),
+//     inject.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+//       http.Error(w, err.Error(), http.StatusUnauthorized)
+//     }),
+//   )
+func ErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) Option {
+	return option(func(container *Container) {
+		container.errorHandler = handler
+	})
+}
+
+// defaultErrorHandler responds with 500 Internal Server Error.
+func defaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+// requestOptions provides request values into the scope.
+func requestOptions(ctx context.Context, w http.ResponseWriter, r *http.Request) []Option {
+	return []Option{
+		Provide(func() *http.Request { return r }),
+		Provide(func() context.Context { return ctx }),
+		Provide(func() http.ResponseWriter { return w }),
+	}
+}
+
+// ContainerFromContext returns the request scope created by Container.Middleware().
+func ContainerFromContext(ctx context.Context) (*Container, bool) {
+	scope, ok := ctx.Value(containerKey{}).(**Container)
+	if !ok || *scope == nil {
+		return nil, false
+	}
+	return *scope, true
+}
+
+// containerKey is a context key of request scope.
+type containerKey struct{}
@@ -0,0 +1,92 @@
+package inject_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/defval/inject/v2"
+)
+
+// User is an authenticated user of request.
+type User string
+
+func TestContainerMiddleware(t *testing.T) {
+	t.Run("middleware provides request scope", func(t *testing.T) {
+		var cleaned int
+		c := inject.New(
+			inject.Provide(ProvideAddr("0.0.0.0", "8080")),
+		)
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			scope, ok := inject.ContainerFromContext(r.Context())
+			require.True(t, ok)
+			require.NoError(t, scope.Invoke(func(ctx context.Context, req *http.Request, rw http.ResponseWriter, user User, addr Addr) {
+				require.Equal(t, r, req)
+				require.Equal(t, r.Context(), ctx)
+				require.Equal(t, w, rw)
+				require.Equal(t, Addr("0.0.0.0:8080"), addr)
+				_, _ = rw.Write([]byte(user))
+			}))
+		})
+		middleware, err := c.Middleware(
+			inject.Provide(func(r *http.Request) (User, func()) {
+				return User(r.Header.Get("X-User")), func() { cleaned++ }
+			}),
+		)
+		require.NoError(t, err)
+		server := middleware(handler)
+		for _, user := range []string{"alice", "bob"} {
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("X-User", user)
+			server.ServeHTTP(recorder, request)
+			require.Equal(t, user, recorder.Body.String())
+		}
+		require.Equal(t, 2, cleaned)
+	})
+
+	t.Run("middleware returns error if scope could not be compiled", func(t *testing.T) {
+		c := inject.New()
+		_, err := c.Middleware(
+			inject.Provide(func(addr Addr) User { return "" }),
+		)
+		require.EqualError(t, err, "inject_test.User: dependency inject_test.Addr not exists in container")
+	})
+
+	t.Run("middleware responds with internal server error if scope could not be created", func(t *testing.T) {
+		c := inject.New()
+		middleware, err := c.Middleware(
+			inject.Invoke(func() error { return errors.New("unauthorized") }),
+		)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		middleware(http.NotFoundHandler()).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("middleware passes scope error into error handler", func(t *testing.T) {
+		c := inject.New()
+		middleware, err := c.Middleware(
+			inject.Invoke(func(r *http.Request) error { return errors.New(r.Header.Get("X-User") + " unauthorized") }),
+			inject.ErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+			}),
+		)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("X-User", "alice")
+		middleware(http.NotFoundHandler()).ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.Equal(t, "alice unauthorized\n", recorder.Body.String())
+	})
+
+	t.Run("context without middleware has no container", func(t *testing.T) {
+		_, ok := inject.ContainerFromContext(context.Background())
+		require.False(t, ok)
+	})
+}
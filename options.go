@@ -1,6 +1,10 @@
 package inject
 
-import "github.com/defval/inject/v2/di"
+import (
+	"time"
+
+	"github.com/defval/inject/v2/di"
+)
 
 // OPTIONS
 
@@ -20,7 +24,8 @@ type Option interface {
 //     }
 //   }
 //
-// Optionally, you can return a cleanup function and initializing error.
+// Optionally, you can return a cleanup function and initializing error. Cleanup function can be func() or
+// func() error.
 //
 //   func NewServer(mux *http.ServeMux) (*http.Server, cleanup func(), err error) {
 //     if time.Now().Day = 1 {
@@ -38,6 +43,20 @@ type Option interface {
 //     return server, cleanup, nil
 //   }
 //
+// A constructor can return several results, each of them is provided as a separate type. A result object is a
+// structure that embeds di.Result, its fields with `di` tag are provided as separate types with tag names. As()
+// interfaces are applied to the results that implement them.
+//
+//   func NewReadWriter() (*Reader, *Writer, error)
+//
+//   type Connections struct {
+//     di.Result
+//     Primary *sql.DB `di:"primary"`
+//     Replica *sql.DB `di:"replica"`
+//   }
+//
+//   func NewConnections(dsn DSN) (Connections, error)
+//
 // Other function signatures will cause error.
 func Provide(provider interface{}, options ...ProvideOption) Option {
 	return option(func(container *Container) {
@@ -80,6 +99,142 @@ func Bundle(options ...Option) Option {
 	})
 }
 
+// Factory returns container option that provides factory function of constructor. The constructor marks runtime
+// arguments with a structure that embeds di.Arguments. The factory takes exported fields of the structure and
+// returns constructor result, cleanup if the constructor returns it, and error, other constructor parameters are
+// resolved from container. Use inject.WithName() to name the factory, other provide options are ignored.
+//
+//   type WorkerArguments struct {
+//     di.Arguments
+//     JobID string
+//   }
+//
+//   func NewWorker(args WorkerArguments, db *sql.DB) (*Worker, error) {
+//     return &Worker{jobID: args.JobID, db: db}, nil
+//   }
+//
+//   inject.Factory(NewWorker) // provides func(jobID string) (*Worker, error)
+func Factory(constructor interface{}, options ...ProvideOption) Option {
+	return option(func(container *Container) {
+		var params = di.ProvideParams{
+			Parameters: map[string]interface{}{},
+		}
+		for _, opt := range options {
+			opt.apply(&params)
+		}
+		container.factories = append(container.factories, provide{
+			provider: constructor,
+			params:   di.ProvideParams{Name: params.Name},
+		})
+	})
+}
+
+// Supply returns container option that adds ready-made value into container. Value is registered by its dynamic
+// type. Cleanup is never run for supplied value.
+//
+//   inject.Supply(&Config{Addr: ":8080"})
+//   inject.Supply(&MemoryStorage{}, inject.WithName("memory"), inject.As(new(Storage)))
+func Supply(value interface{}, options ...ProvideOption) Option {
+	return option(func(container *Container) {
+		var params = di.ProvideParams{
+			Parameters: map[string]interface{}{},
+		}
+		for _, opt := range options {
+			opt.apply(&params)
+		}
+		container.supplies = append(container.supplies, provide{
+			provider: value,
+			params:   params,
+		})
+	})
+}
+
+// Replace returns container option that replaces provided type with a new constructor. Interfaces and groups of
+// the replaced type are kept. Replacements are applied after all providers, so it is useful to override a type of
+// production bundle in tests.
+//
+//   container := inject.New(
+//     ProductionBundle,
+//     inject.Replace(NewFakeUserRepository),
+//   )
+func Replace(provider interface{}, options ...ProvideOption) Option {
+	return option(func(container *Container) {
+		var params = di.ProvideParams{
+			Parameters: map[string]interface{}{},
+		}
+		for _, opt := range options {
+			opt.apply(&params)
+		}
+		container.replacements = append(container.replacements, provide{
+			provider: provider,
+			params:   params,
+		})
+	})
+}
+
+// Decorate returns container option that wraps provided type into decorator. The decorator takes the original
+// instance and its own dependencies and returns the decorated instance of the same type. Every consumer of the
+// type, including interfaces and groups of the type, receives the decorated instance. Use inject.WithName() to
+// decorate named type, other provide options are ignored.
+//
+//   inject.Provide(NewStorage, inject.As(new(Storage))),
+//   inject.Decorate(func(storage Storage, logger *log.Logger) Storage {
+//     return &loggingStorage{storage: storage, logger: logger}
+//   }),
+func Decorate(decorator interface{}, options ...ProvideOption) Option {
+	return option(func(container *Container) {
+		var params = di.ProvideParams{
+			Parameters: map[string]interface{}{},
+		}
+		for _, opt := range options {
+			opt.apply(&params)
+		}
+		container.decorators = append(container.decorators, provide{
+			provider: decorator,
+			params:   di.ProvideParams{Name: params.Name},
+		})
+	})
+}
+
+// Invoke returns container option that invokes function after container compile. Dependencies of function will
+// be resolved via container. Invoke is useful to build components that nobody depends on.
+//
+//   inject.New(
+//     inject.Provide(NewServeMux),
+//     inject.Invoke(func(mux *http.ServeMux, controller *AccountController) {
+//       controller.RegisterRoutes(mux)
+//     }),
+//   )
+func Invoke(fn interface{}) Option {
+	return option(func(container *Container) {
+		container.invokes = append(container.invokes, fn)
+	})
+}
+
+// AutoBind enables automatic interface binding. Dependency on interface without inject.As() is resolved by the only
+// provided type that implements the interface, dependency on group of interface collects all provided types that
+// implement it, with or without inject.As(). Several implementations of the interface cause compile error for its
+// consumers.
+//
+//   inject.New(
+//     inject.AutoBind(),
+//     inject.Provide(NewServeMux), // *http.ServeMux is used as http.Handler
+//     inject.Provide(NewServer),
+//   )
+func AutoBind() Option {
+	return option(func(container *Container) {
+		container.container.EnableAutoBinding()
+	})
+}
+
+// ShutdownTimeout sets a time that application waits for stop hooks and cleanups on shutdown. Default is
+// DefaultShutdownTimeout.
+func ShutdownTimeout(timeout time.Duration) Option {
+	return option(func(container *Container) {
+		container.shutdownTimeout = timeout
+	})
+}
+
 // ProvideOption modifies default provide behavior. See inject.WithName(), inject.As(), inject.Prototype().
 type ProvideOption interface {
 	apply(params *di.ProvideParams)
@@ -107,10 +262,83 @@ func WithName(name string) ProvideOption {
 //
 //   var handlers []http.Handler
 //   container.Extract(&handlers) // extract group
+//
+// Use inject.Group() to add the instance into named group and inject.Binding() to name the interface binding.
+// They are applied to every interface of the As() call.
+//
+//   Provide(NewAdminMux, inject.As(new(http.Handler), inject.Group("admin")))
+//
+//   var handlers []http.Handler
+//   container.Extract(&handlers, inject.Name("admin")) // extract named group
 func As(ifaces ...interface{}) ProvideOption {
 	return provideOption(func(provider *di.ProvideParams) {
-		provider.Interfaces = append(provider.Interfaces, ifaces...)
+		var binding di.Interface
+		for _, iface := range ifaces {
+			if marker, ok := iface.(asMarker); ok {
+				marker(&binding)
+			}
+		}
+		for _, iface := range ifaces {
+			if _, ok := iface.(asMarker); ok {
+				continue
+			}
+			if binding.Name == "" && binding.Group == "" {
+				provider.Interfaces = append(provider.Interfaces, iface)
+				continue
+			}
+			provider.Interfaces = append(provider.Interfaces, di.Interface{
+				Type:  iface,
+				Name:  binding.Name,
+				Group: binding.Group,
+			})
+		}
+	})
+}
+
+// Group returns As() marker that adds instance into named interface group. Members of named group are not members of
+// the default group.
+func Group(name string) interface{} {
+	return asMarker(func(binding *di.Interface) {
+		binding.Group = name
+	})
+}
 
+// Binding returns As() marker that sets name of interface binding. Provider name is used by default.
+func Binding(name string) interface{} {
+	return asMarker(func(binding *di.Interface) {
+		binding.Name = name
+	})
+}
+
+// asMarker modifies interface binding of As().
+type asMarker func(binding *di.Interface)
+
+// Priority sets order of instance in interface groups. Instances with lower priority go first, instances with the
+// same priority keep the order of providing. Default priority is 0.
+//
+//   inject.Provide(NewRecoveryMiddleware, inject.As(new(Middleware)), inject.Priority(-10))
+//   inject.Provide(NewLoggingMiddleware, inject.As(new(Middleware)))
+//
+//   var middlewares []Middleware
+//   container.Extract(&middlewares) // recovery, logging
+func Priority(priority int) ProvideOption {
+	return provideOption(func(provider *di.ProvideParams) {
+		provider.Priority = priority
+	})
+}
+
+// Primary marks instance as the default implementation of its interfaces when several providers implement them.
+// Groups still contain all implementations. Container returns an error if an interface has two primary
+// implementations.
+//
+//   inject.Provide(NewPostgresStorage, inject.As(new(Storage)), inject.Primary())
+//   inject.Provide(NewMemoryStorage, inject.As(new(Storage)))
+//
+//   var storage Storage
+//   container.Extract(&storage) // postgres storage
+func Primary() ProvideOption {
+	return provideOption(func(provider *di.ProvideParams) {
+		provider.Primary = true
 	})
 }
 